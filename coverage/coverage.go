@@ -0,0 +1,176 @@
+// Package coverage is an internal package of the tool Crawl,
+// responsible for comparing the URLs found by following links with
+// those listed in sitemaps. A URL listed in sitemaps but not linked
+// is an orphan; a URL linked but not listed is missing from the
+// sitemaps.
+package coverage
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+	"github.com/benjaminestes/crawl/sitemap"
+)
+
+// These are the values of Row.Coverage.
+const (
+	SitemapOnly = "sitemap only"
+	LinksOnly   = "links only"
+	Both        = "both"
+)
+
+// Row says whether a URL was found by following links, by reading
+// sitemaps, or both. Depth is only recorded for URLs found by
+// following links, and Sitemap for URLs found in sitemaps.
+type Row struct {
+	Address    string
+	Coverage   string
+	StatusCode int
+	Depth      *int   `json:",omitempty"`
+	Sitemap    string `json:",omitempty"`
+
+	listed bool
+}
+
+// Coverage is the set of URLs found by following links or by reading
+// sitemaps, in the order they were found. The zero value is an empty
+// set.
+type Coverage struct {
+	rows  map[string]*Row
+	order []string
+}
+
+func (cv *Coverage) row(addr string) *Row {
+	if cv.rows == nil {
+		cv.rows = make(map[string]*Row)
+	}
+	row, ok := cv.rows[addr]
+	if !ok {
+		row = &Row{Address: addr}
+		cv.rows[addr] = row
+		cv.order = append(cv.order, addr)
+	}
+	return row
+}
+
+// Linked says whether addr was found by following links.
+func (cv *Coverage) Linked(addr string) bool {
+	row, ok := cv.rows[addr]
+	return ok && row.Depth != nil
+}
+
+// AddLinked records a result of a crawl following links.
+func (cv *Coverage) AddLinked(r *data.Result) {
+	if r.Address == nil {
+		return
+	}
+	row := cv.row(r.Address.Full)
+	depth := r.Depth
+	row.Depth = &depth
+	row.StatusCode = r.StatusCode
+}
+
+// AddListed records a URL listed in sitemap, with the result of
+// crawling it, if any.
+func (cv *Coverage) AddListed(addr, sitemap string, r *data.Result) {
+	row := cv.row(addr)
+	row.listed = true
+	if row.Sitemap == "" {
+		row.Sitemap = sitemap
+	}
+	if row.Depth == nil && r != nil {
+		row.StatusCode = r.StatusCode
+	}
+}
+
+// AddListedResult records the result of crawling a URL listed in a
+// sitemap.
+func (cv *Coverage) AddListedResult(r *data.Result) {
+	if r.Address == nil {
+		return
+	}
+	var sitemap string
+	if r.SitemapEntry != nil {
+		sitemap = r.SitemapEntry.Sitemap
+	}
+	cv.AddListed(r.Address.Full, sitemap, r)
+}
+
+// Orphans records the sitemap entries that were found by following
+// links, and returns the rest, which must be crawled to be recorded.
+// Entries are skipped if they aren't on the host of their sitemap, as
+// the protocol requires, or if inScope returns false for them, so
+// that the same scope applies to URLs in sitemaps as to links. The
+// number of entries skipped is returned.
+func (cv *Coverage) Orphans(entries []*sitemap.Entry, inScope func(string) bool) ([]*sitemap.Entry, int) {
+	var orphans []*sitemap.Entry
+	var skipped int
+	for _, e := range entries {
+		addr := data.MakeAddress(e.Loc)
+		if addr == nil || !sameHost(addr.Full, e.Sitemap) || !inScope(addr.Full) {
+			skipped++
+			continue
+		}
+		if cv.Linked(addr.Full) {
+			cv.AddListed(addr.Full, e.Sitemap, nil)
+			continue
+		}
+		orphans = append(orphans, e)
+	}
+	return orphans, skipped
+}
+
+// sameHost says whether a and b have the same scheme and host. A URL
+// without a sitemap is on its host.
+func sameHost(a, b string) bool {
+	if b == "" {
+		return true
+	}
+	u, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	v, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == v.Scheme && strings.EqualFold(u.Host, v.Host)
+}
+
+// Rows returns the URLs recorded, in the order they were found, with
+// their Coverage set.
+func (cv *Coverage) Rows() []*Row {
+	var rows []*Row
+	for _, addr := range cv.order {
+		row := cv.rows[addr]
+		switch {
+		case row.listed && row.Depth != nil:
+			row.Coverage = Both
+		case row.listed:
+			row.Coverage = SitemapOnly
+		default:
+			row.Coverage = LinksOnly
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// ReadResults passes each result in the crawl data in to f.
+func ReadResults(in io.Reader, f func(*data.Result)) error {
+	dec := json.NewDecoder(in)
+	for {
+		var r data.Result
+		err := dec.Decode(&r)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		f(&r)
+	}
+}
@@ -0,0 +1,98 @@
+package coverage
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+	"github.com/benjaminestes/crawl/sitemap"
+)
+
+func result(addr string, depth, status int) *data.Result {
+	r := &data.Result{Depth: depth}
+	r.Address = data.MakeAddress(addr)
+	r.StatusCode = status
+	return r
+}
+
+func TestRows(t *testing.T) {
+	cv := &Coverage{}
+	cv.AddLinked(result("http://example.com/", 0, 200))
+	cv.AddLinked(result("http://example.com/a", 1, 200))
+	cv.AddListed("http://example.com/a", "http://example.com/sitemap.xml", nil)
+	cv.AddListed("http://example.com/b", "http://example.com/sitemap.xml", result("http://example.com/b", 0, 404))
+
+	expected := []struct {
+		addr     string
+		coverage string
+		status   int
+	}{
+		{"http://example.com/", LinksOnly, 200},
+		{"http://example.com/a", Both, 200},
+		{"http://example.com/b", SitemapOnly, 404},
+	}
+
+	rows := cv.Rows()
+	if len(rows) != len(expected) {
+		t.Fatalf("expected %d rows, got %d", len(expected), len(rows))
+	}
+	for i, row := range rows {
+		e := expected[i]
+		if row.Address != e.addr || row.Coverage != e.coverage || row.StatusCode != e.status {
+			t.Errorf("row %d: expected %s %q %d, got %s %q %d",
+				i, e.addr, e.coverage, e.status, row.Address, row.Coverage, row.StatusCode)
+		}
+	}
+	if rows[2].Depth != nil {
+		t.Errorf("expected no depth for URL only in sitemap, got %d", *rows[2].Depth)
+	}
+}
+
+func TestOrphans(t *testing.T) {
+	const sm = "http://example.com/sitemap.xml"
+	cv := &Coverage{}
+	cv.AddLinked(result("http://example.com/linked", 1, 200))
+
+	entries := []*sitemap.Entry{
+		{Loc: "http://example.com/linked", Sitemap: sm},
+		{Loc: "http://example.com/orphan", Sitemap: sm},
+		{Loc: "http://other.com/page", Sitemap: sm},
+		{Loc: "https://example.com/page", Sitemap: sm},
+		{Loc: "http://example.com/private/page", Sitemap: sm},
+	}
+	inScope := func(addr string) bool {
+		return !strings.Contains(addr, "/private/")
+	}
+
+	orphans, skipped := cv.Orphans(entries, inScope)
+	if skipped != 3 {
+		t.Errorf("expected 3 entries skipped, got %d", skipped)
+	}
+	if len(orphans) != 1 || orphans[0].Loc != "http://example.com/orphan" {
+		t.Errorf("expected only orphan, got %v", orphans)
+	}
+
+	rows := cv.Rows()
+	if len(rows) != 1 || rows[0].Coverage != Both || rows[0].Sitemap != sm {
+		t.Errorf("expected linked URL recorded as in both, got %+v", rows[0])
+	}
+}
+
+func TestReadResults(t *testing.T) {
+	in := strings.NewReader(`{"Address":{"Full":"http://example.com/"},"Depth":0,"StatusCode":200}
+{"Address":{"Full":"http://example.com/a"},"Depth":1,"StatusCode":301}
+`)
+	var addrs []string
+	if err := ReadResults(in, func(r *data.Result) {
+		addrs = append(addrs, r.Address.Full)
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(addrs) != 2 || addrs[1] != "http://example.com/a" {
+		t.Errorf("unexpected results %v", addrs)
+	}
+
+	if err := ReadResults(strings.NewReader(`{"Address":`), func(*data.Result) {}); err == nil {
+		t.Errorf("expected error for truncated crawl data")
+	}
+}
@@ -11,17 +11,24 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
-	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
+	"github.com/benjaminestes/crawl/coverage"
 	"github.com/benjaminestes/crawl/crawler"
+	"github.com/benjaminestes/crawl/crawler/data"
+	"github.com/benjaminestes/crawl/fingerprint"
+	"github.com/benjaminestes/crawl/robotstxt"
 	"github.com/benjaminestes/crawl/schema"
 	"github.com/benjaminestes/crawl/sitemap"
 )
@@ -30,8 +37,39 @@ var (
 	spiderCommand = flag.NewFlagSet("spider", flag.ExitOnError)
 	listCommand   = flag.NewFlagSet("list", flag.ExitOnError)
 	listType      = listCommand.String("format",
-		"text", "format of input for list mode: {text|xml}")
-	sitemapCommand = flag.NewFlagSet("sitemap", flag.ExitOnError)
+		"text", "format of input for list mode: {text|txt-sitemap|auto|xml|rss|atom|csv}")
+	listColumn = listCommand.String("column",
+		"1", "CSV column containing URLs, by number or header")
+	sitemapCommand  = flag.NewFlagSet("sitemap", flag.ExitOnError)
+	sitemapMaxDepth = sitemapCommand.Int("maxdepth",
+		0, "maximum nesting of sitemap indexes, 0 for SitemapMaxDepth, or -1 for no limit")
+	sitemapMaxURLs = sitemapCommand.Int("maxurls",
+		0, "maximum number of URLs to crawl, 0 for SitemapMaxURLs, or -1 for no limit")
+	sitemapValidate = sitemapCommand.Bool("validate",
+		false, "print problems with the sitemaps instead of crawl data")
+	schemaCommand = flag.NewFlagSet("schema", flag.ExitOnError)
+	schemaType    = schemaCommand.String("type",
+		"result", "type of output to describe: {result|finding}")
+	mksitemapCommand = flag.NewFlagSet("mksitemap", flag.ExitOnError)
+	mksitemapDir     = mksitemapCommand.String("dir",
+		".", "directory to write sitemaps to")
+	mksitemapName = mksitemapCommand.String("name",
+		"sitemap", "prefix of the names of the sitemap files")
+	mksitemapBase = mksitemapCommand.String("base",
+		"", "URL of the directory the sitemaps will be published in")
+	mksitemapGzip = mksitemapCommand.Bool("gzip",
+		false, "gzip-compress the sitemaps")
+	robotsCommand = flag.NewFlagSet("robots", flag.ExitOnError)
+	robotsAgents  = robotsCommand.String("ua",
+		"Crawler", "comma-separated user-agents to test")
+	robotsLive = robotsCommand.Bool("live",
+		false, "when testing a file, also test the live robots.txt of each URL")
+	coverageCommand = flag.NewFlagSet("coverage", flag.ExitOnError)
+	dupesCommand    = flag.NewFlagSet("dupes", flag.ExitOnError)
+	dupesThreshold  = dupesCommand.Float64("threshold",
+		0.9, "minimum similarity of near-duplicate pages, greater than 0 and at most 1")
+	dupesMethod = dupesCommand.String("method",
+		"simhash", "fingerprint to compare: {simhash|minhash}")
 )
 
 func main() {
@@ -52,6 +90,14 @@ func main() {
 		doList()
 	case "sitemap":
 		doSitemap()
+	case "coverage":
+		doCoverage()
+	case "robots":
+		doRobots()
+	case "dupes":
+		doDupes()
+	case "mksitemap":
+		doMksitemap()
 	default:
 		fmt.Fprintf(os.Stderr, "unexpected command: %s\n", os.Args[1])
 		fmt.Fprintf(os.Stderr, `run "crawl help" for usage`+"\n")
@@ -60,7 +106,15 @@ func main() {
 }
 
 func doSchema() {
-	os.Stdout.Write(schema.BigQueryJSON())
+	schemaCommand.Parse(os.Args[2:])
+	switch *schemaType {
+	case "result":
+		os.Stdout.Write(schema.BigQueryJSON())
+	case "finding":
+		os.Stdout.Write(schema.FindingBigQueryJSON())
+	default:
+		log.Fatalf("unknown schema type: %s", *schemaType)
+	}
 	fmt.Println()
 }
 
@@ -89,23 +143,85 @@ func doSitemap() {
 	if err != nil {
 		log.Fatalf("%v", err)
 	}
-	if sitemapCommand.NArg() < 2 {
-		log.Fatal(fmt.Errorf("expected sitemap URL"))
-	}
-	var queue []string
-	queue, err = fetchAll(sitemapCommand.Arg(1))
-	if err != nil {
-		log.Fatal(fmt.Errorf("error fetching sitemap"))
-	}
 	c, err := crawler.FromJSON(config)
 	if err != nil {
 		log.Fatalf("couldn't parse JSON config: %v", err)
 	}
-	c.From = queue
+	// The crawl is of the sitemaps' URLs alone.
+	c.CrawlSitemaps = false
+	if *sitemapMaxDepth != 0 {
+		c.SitemapMaxDepth = *sitemapMaxDepth
+	}
+	if *sitemapMaxURLs != 0 {
+		c.SitemapMaxURLs = *sitemapMaxURLs
+	}
+	f := c.SitemapFetcher()
+	if *sitemapValidate {
+		f.Validator = &sitemap.Validator{}
+	}
+
+	// If no sitemap is given, look for the sitemaps of the sites
+	// in the configuration.
+	var sitemaps []*sitemap.Discovered
+	for _, s := range sitemapCommand.Args()[1:] {
+		sitemaps = append(sitemaps, &sitemap.Discovered{URL: s})
+	}
+	if len(sitemaps) == 0 {
+		for _, site := range c.From {
+			found, err := f.Discover(site)
+			if err != nil {
+				log.Printf("couldn't discover sitemaps of %s: %v", site, err)
+				continue
+			}
+			for _, s := range found {
+				log.Printf("discovered sitemap %s", s)
+			}
+			sitemaps = append(sitemaps, found...)
+		}
+		if len(sitemaps) == 0 {
+			log.Fatal(fmt.Errorf("no sitemaps found"))
+		}
+	}
+
+	c.From = nil
+	for _, s := range sitemaps {
+		log.Printf("retrieving sitemap %s", s)
+		entries, errs := f.FetchDiscovered(s)
+		for _, err := range errs {
+			log.Printf("%v", err)
+			if f.Validator != nil {
+				f.Validator.AddError(err)
+			}
+		}
+		c.AddSitemapEntries(entries)
+	}
+	if len(c.From) == 0 && f.Validator == nil {
+		log.Fatal(fmt.Errorf("error fetching sitemap"))
+	}
+	if f.MaxURLs > 0 && len(c.From) > f.MaxURLs {
+		c.From = c.From[:f.MaxURLs]
+	}
 	c.MaxDepth = 0
+	if f.Validator != nil {
+		doValidate(c, f.Validator)
+		return
+	}
 	doCrawl(c)
 }
 
+// doValidate prints the findings of v, followed by those made by
+// crawling the URLs listed in the sitemaps.
+func doValidate(c *crawler.Crawler, v *sitemap.Validator) {
+	if len(c.From) > 0 {
+		crawlEach(c, v.AddResult)
+	}
+	for _, f := range v.Findings {
+		j, _ := json.Marshal(f)
+		fmt.Printf("%s\n", j)
+	}
+	log.Printf("validation complete, %d findings", len(v.Findings))
+}
+
 func doList() {
 	listCommand.Parse(os.Args[2:])
 	if listCommand.NArg() < 1 {
@@ -115,27 +231,87 @@ func doList() {
 	if err != nil {
 		log.Fatal(fmt.Errorf("%v", err))
 	}
-	var queue []string
-	switch *listType {
-	case "text":
-		queue = listFromReader(os.Stdin)
-		// FIXME: Here to justify listType existence.
-	case "xml":
-		queue, err = sitemap.Parse(os.Stdin)
-		if err != nil {
-			log.Fatalf("couldn't parse sitemap from stdin: %v", err)
-		}
-	}
 	c, err := crawler.FromJSON(config)
 	if err != nil {
 		log.Fatal(fmt.Errorf("%v", err))
 	}
-	c.From = queue
+	in, err := readList(os.Stdin)
+	if err != nil {
+		log.Fatalf("couldn't read list from stdin: %v", err)
+	}
+	format := *listType
+	if format == "auto" {
+		format = sitemap.DetectFormat(in)
+		log.Printf("reading list in %s format", format)
+	}
+	// The crawl is of the list alone.
+	c.CrawlSitemaps = false
+	c.From = nil
+	switch format {
+	// The protocol's name for a text file is txt-sitemap.
+	case "text", "txt-sitemap":
+		c.From = listFromReader(bytes.NewReader(in))
+	case "csv":
+		c.From, err = sitemap.ParseCSV(bytes.NewReader(in), *listColumn)
+		if err != nil {
+			log.Fatalf("couldn't parse CSV from stdin: %v", err)
+		}
+	default:
+		parse, ok := entryParsers[format]
+		if !ok {
+			log.Fatalf("unknown list format: %s", format)
+		}
+		entries, err := parse(bytes.NewReader(in))
+		if err != nil {
+			log.Fatalf("couldn't parse %s from stdin: %v", format, err)
+		}
+		c.AddSitemapEntries(entries)
+	}
 	c.MaxDepth = 0
 	doCrawl(c)
 }
 
+// entryParsers are the list formats that the sitemap protocol
+// accepts, other than plain text.
+var entryParsers = map[string]func(io.Reader) ([]*sitemap.Entry, error){
+	"xml":  sitemap.ParseEntries,
+	"rss":  sitemap.ParseRSS,
+	"atom": sitemap.ParseAtom,
+}
+
+// readList reads a list from in, decompressing it if it is
+// gzip-compressed, whatever its format.
+func readList(in io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return ioutil.ReadAll(zr)
+}
+
+func isAbsURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	return err == nil && u.IsAbs()
+}
+
 func doCrawl(c *crawler.Crawler) {
+	crawlEach(c, func(n *data.Result) {
+		j, _ := json.Marshal(n)
+		fmt.Printf("%s\n", j)
+	})
+}
+
+// crawlEach runs the crawl c, passing each result to f and logging
+// its progress.
+func crawlEach(c *crawler.Crawler, f func(*data.Result)) {
 	count, lastCount := 0, 0
 	lastUpdate := time.Now()
 	err := c.Start()
@@ -145,8 +321,7 @@ func doCrawl(c *crawler.Crawler) {
 	}
 	log.Printf("crawl started")
 	for n := c.Next(); n != nil; n = c.Next() {
-		j, _ := json.Marshal(n)
-		fmt.Printf("%s\n", j)
+		f(n)
 		count++
 		if time.Since(lastUpdate) > 5*time.Second {
 			lastUpdate = time.Now()
@@ -159,88 +334,422 @@ func doCrawl(c *crawler.Crawler) {
 	log.Printf("crawl complete, %d URLs total", count)
 }
 
-func listFromReader(in io.Reader) []string {
-	var queue []string
-	scanner := bufio.NewScanner(in)
-	for scanner.Scan() {
-		queue = append(queue, scanner.Text())
+func doCoverage() {
+	coverageCommand.Parse(os.Args[2:])
+	cv := &coverage.Coverage{}
+	switch coverageCommand.NArg() {
+	case 1:
+		coverageCrawl(cv, coverageCommand.Arg(0))
+	case 2:
+		readResults(coverageCommand.Arg(0), cv.AddLinked)
+		readResults(coverageCommand.Arg(1), cv.AddListedResult)
+	default:
+		log.Fatal(fmt.Errorf("expected location of config file, or of spider and sitemap crawl data"))
 	}
-	return queue
+
+	counts := make(map[string]int)
+	for _, row := range cv.Rows() {
+		counts[row.Coverage]++
+		j, _ := json.Marshal(row)
+		fmt.Printf("%s\n", j)
+	}
+	log.Printf("%d URLs in sitemaps only, %d reachable by links only, %d in both",
+		counts[coverage.SitemapOnly], counts[coverage.LinksOnly], counts[coverage.Both])
 }
 
-// fetchAll recursively produces a list of all URLs represented by the
-// sitemap (index?) at url. If url points to a sitemap index, all of
-// the sitemaps within that index will be recursively
-// requested. Requests are not concurrent.
-func fetchAll(url string) ([]string, error) {
-	log.Printf("retrieving sitemap %s", url)
+// coverageCrawl crawls the sites configured in the file config by
+// following links, and then crawls the URLs listed in their sitemaps
+// that weren't found that way. Sitemaps are read within the
+// configured SitemapMaxDepth and SitemapMaxURLs, and their URLs are
+// subject to Include and Exclude like links.
+func coverageCrawl(cv *coverage.Coverage, config string) {
+	configJSON, err := ioutil.ReadFile(config)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	spider, err := crawler.FromJSON(bytes.NewReader(configJSON))
+	if err != nil {
+		log.Fatalf("couldn't parse JSON config: %v", err)
+	}
+	spider.CrawlSitemaps = false
 
-	resp, err := http.Get(url)
+	f := spider.SitemapFetcher()
+	var entries []*sitemap.Entry
+	for _, site := range spider.From {
+		found, err := f.Discover(site)
+		if err != nil {
+			log.Printf("couldn't discover sitemaps of %s: %v", site, err)
+			continue
+		}
+		for _, s := range found {
+			if f.MaxURLs > 0 && len(entries) >= f.MaxURLs {
+				break
+			}
+			log.Printf("retrieving sitemap %s", s)
+			e, errs := f.FetchDiscovered(s)
+			for _, err := range errs {
+				log.Printf("%v", err)
+			}
+			entries = append(entries, e...)
+		}
+	}
+	if f.MaxURLs > 0 && len(entries) > f.MaxURLs {
+		log.Printf("sitemap URL limit of %d reached", f.MaxURLs)
+		entries = entries[:f.MaxURLs]
+	}
+
+	crawlEach(spider, cv.AddLinked)
+
+	list, err := crawler.FromJSON(bytes.NewReader(configJSON))
 	if err != nil {
-		log.Fatalf("error retrieving sitemap %s: %v", url, err)
+		log.Fatalf("couldn't parse JSON config: %v", err)
+	}
+	list.From = nil
+	list.MaxDepth = 0
+	list.CrawlSitemaps = false
+	orphans, skipped := cv.Orphans(entries, spider.InScope)
+	if skipped > 0 {
+		log.Printf("skipped %d sitemap URLs out of scope", skipped)
+	}
+	list.AddSitemapEntries(orphans)
+	if len(list.From) > 0 {
+		crawlEach(list, cv.AddListedResult)
 	}
-	defer resp.Body.Close()
+}
 
-	// It's possible we will need to try to parse the response
-	// body twice, so read to []byte.
-	data, err := ioutil.ReadAll(resp.Body)
+// readResults passes each result in the crawl data in the file name
+// to f.
+func readResults(name string, f func(*data.Result)) {
+	in, err := os.Open(name)
 	if err != nil {
-		log.Fatalf("error reading content of sitemap %s: %v", url, err)
+		log.Fatalf("%v", err)
+	}
+	defer in.Close()
+	if err := coverage.ReadResults(in, f); err != nil {
+		log.Fatalf("couldn't parse crawl data from %s: %v", name, err)
 	}
+}
 
-	var urls []string
+func doRobots() {
+	robotsCommand.Parse(os.Args[2:])
+	if robotsCommand.NArg() < 1 {
+		log.Fatal(fmt.Errorf("expected URL or location of robots.txt file"))
+	}
+	source := robotsCommand.Arg(0)
+	urls := robotsCommand.Args()[1:]
 
-	urls, err = sitemap.Parse(bytes.NewReader(data))
+	var draft *robotstxt.Source
+	var err error
+	isURL := isAbsURL(source)
+	if isURL {
+		draft, err = robotstxt.Fetch(source)
+		// A URL other than a robots.txt file is also the URL
+		// to test, if no other is given.
+		if err == nil && len(urls) == 0 && source != draft.Location {
+			urls = []string{source}
+		}
+	} else {
+		draft, err = robotstxt.Load(source)
+	}
 	if err != nil {
-		return nil, err
+		log.Fatalf("couldn't read robots.txt: %v", err)
 	}
 
-	if len(urls) > 0 {
-		return urls, nil
+	if len(urls) == 0 {
+		urls, err = sitemap.ParseList(os.Stdin)
+		if err != nil {
+			log.Fatalf("couldn't read URLs from stdin: %v", err)
+		}
 	}
 
-	sitemaps, err := sitemap.ParseIndex(bytes.NewReader(data))
-	if err != nil {
-		return nil, err
+	var live robotstxt.Live
+	for _, u := range urls {
+		for _, ua := range strings.Split(*robotsAgents, ",") {
+			ua = strings.TrimSpace(ua)
+			row := draft.Test(ua, u)
+			if *robotsLive && !isURL {
+				if err := live.Test(row); err != nil {
+					log.Printf("couldn't test %s against live robots.txt: %v", u, err)
+				}
+			}
+			j, _ := json.Marshal(row)
+			fmt.Printf("%s\n", j)
+		}
 	}
+}
 
-	for _, s := range sitemaps {
-		newurls, err := fetchAll(s)
+// dupeCluster is a group of pages whose content is nearly identical.
+type dupeCluster struct {
+	Size      int
+	Addresses []string
+}
+
+func doDupes() {
+	dupesCommand.Parse(os.Args[2:])
+	if *dupesThreshold <= 0 || *dupesThreshold > 1 {
+		log.Fatal(fmt.Errorf("threshold must be greater than 0 and at most 1"))
+	}
+	if *dupesMethod != "simhash" && *dupesMethod != "minhash" {
+		log.Fatalf("unknown fingerprint method: %s", *dupesMethod)
+	}
+
+	var addrs []string
+	var hashes []uint64
+	var sigs [][]uint64
+	dec := json.NewDecoder(os.Stdin)
+	for {
+		var r data.Result
+		err := dec.Decode(&r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatalf("couldn't parse crawl data from stdin: %v", err)
+		}
+		if r.Address == nil {
+			continue
+		}
+		switch *dupesMethod {
+		case "simhash":
+			if r.SimHash == nil {
+				continue
+			}
+			hashes = append(hashes, uint64(*r.SimHash))
+		case "minhash":
+			if len(r.MinHash) == 0 {
+				continue
+			}
+			sig := make([]uint64, len(r.MinHash))
+			for i, v := range r.MinHash {
+				sig[i] = uint64(v)
+			}
+			sigs = append(sigs, sig)
+		}
+		addrs = append(addrs, r.Address.Full)
+	}
+
+	var groups [][]int
+	if *dupesMethod == "minhash" {
+		groups = fingerprint.ClusterMinHash(sigs, *dupesThreshold)
+	} else {
+		groups = fingerprint.ClusterSimHash(hashes, *dupesThreshold)
+	}
+
+	for _, g := range groups {
+		cluster := &dupeCluster{Size: len(g)}
+		for _, i := range g {
+			cluster.Addresses = append(cluster.Addresses, addrs[i])
+		}
+		j, _ := json.Marshal(cluster)
+		fmt.Printf("%s\n", j)
+	}
+	log.Printf("found %d groups of near-duplicate pages among %d pages", len(groups), len(addrs))
+}
+
+func doMksitemap() {
+	mksitemapCommand.Parse(os.Args[2:])
+
+	var col sitemap.Collector
+	if err := coverage.ReadResults(os.Stdin, col.Add); err != nil {
+		log.Fatalf("couldn't parse crawl data from stdin: %v", err)
+	}
+	entries := col.Entries
+	if len(entries) == 0 {
+		log.Fatal(fmt.Errorf("no indexable pages in crawl data"))
+	}
+
+	base := *mksitemapBase
+	if base == "" {
+		u, err := url.Parse(entries[0].Loc)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		base = u.Scheme + "://" + u.Host + "/"
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+
+	entries, skipped := sitemap.Under(entries, base)
+	if skipped > 0 {
+		log.Printf("skipped %d pages not under %s", skipped, base)
+	}
+	if len(entries) == 0 {
+		log.Fatal(fmt.Errorf("no indexable pages under %s", base))
+	}
+
+	groups, err := sitemap.Split(entries)
+	if err != nil {
+		log.Fatalf("couldn't encode sitemap: %v", err)
+	}
+	ext := ".xml"
+	if *mksitemapGzip {
+		ext = ".xml.gz"
+	}
+	var locs []string
+	for i, group := range groups {
+		name := fmt.Sprintf("%s-%d%s", *mksitemapName, i+1, ext)
+		err := writeSitemapFile(name, func(w io.Writer) error {
+			return sitemap.Write(w, group)
+		})
 		if err != nil {
-			return nil, err
+			log.Fatalf("couldn't write sitemap %s: %v", name, err)
 		}
-		urls = append(urls, newurls...)
+		locs = append(locs, base+name)
+		log.Printf("wrote %d URLs to %s", len(group), name)
 	}
 
-	return urls, nil
+	name := *mksitemapName + "-index" + ext
+	err = writeSitemapFile(name, func(w io.Writer) error {
+		return sitemap.WriteIndex(w, locs)
+	})
+	if err != nil {
+		log.Fatalf("couldn't write sitemap index %s: %v", name, err)
+	}
+	log.Printf("wrote index of %d sitemaps to %s", len(locs), name)
+}
+
+// writeSitemapFile creates the file name in the directory given by
+// -dir, and writes to it using write, compressing it if requested.
+func writeSitemapFile(name string, write func(io.Writer) error) error {
+	f, err := os.Create(filepath.Join(*mksitemapDir, name))
+	if err != nil {
+		return err
+	}
+	var w io.Writer = f
+	var zw *gzip.Writer
+	if *mksitemapGzip {
+		zw = gzip.NewWriter(f)
+		w = zw
+	}
+	if err := write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+func listFromReader(in io.Reader) []string {
+	var queue []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		queue = append(queue, scanner.Text())
+	}
+	return queue
 }
 
 func doHelp() {
 	fmt.Println("USAGE: crawl <command> [-flags] [args]")
 	fmt.Println()
 	fmt.Println("The following commands are valid:")
-	fmt.Println("\thelp, list, schema, sitemap, spider")
+	fmt.Println("\tcoverage, dupes, help, list, mksitemap, robots, schema, sitemap,")
+	fmt.Println("\tspider")
+	fmt.Println()
+	fmt.Println("coverage\tCompare the URLs listed in sitemaps with those reachable by")
+	fmt.Println("\t\tlinks. Given a config file, the sites in From are crawled by")
+	fmt.Println("\t\tfollowing links, and their sitemaps are found and read")
+	fmt.Println("\t\twithin SitemapMaxDepth and SitemapMaxURLs. Sitemap URLs on")
+	fmt.Println("\t\tother hosts or outside Include and Exclude are skipped.")
+	fmt.Println("\t\tAlternatively, given the output of a spider crawl and of a")
+	fmt.Println("\t\tsitemap crawl, those are compared. For each URL, it prints")
+	fmt.Println("\t\twhether it was found in sitemaps only (an orphan), by links")
+	fmt.Println("\t\tonly (missing from sitemaps), or both, with its status code")
+	fmt.Println("\t\tand depth.")
+	fmt.Println()
+	fmt.Println("\t\tExample:")
+	fmt.Println("\t\tcrawl coverage config.json >coverage.txt")
+	fmt.Println("\t\tcrawl coverage spider.txt sitemap.txt >coverage.txt")
+	fmt.Println()
+	fmt.Println("dupes\t\tGroup near-duplicate pages in crawl output provided on stdin.")
+	fmt.Println()
+	fmt.Println("\t\tThe -threshold flag sets the minimum similarity (default 0.9).")
+	fmt.Println("\t\tThe -method={simhash|minhash} flag selects the fingerprint.")
+	fmt.Println()
+	fmt.Println("\t\tExample:")
+	fmt.Println("\t\tcrawl dupes -threshold=0.95 <out.txt >dupes.txt")
 	fmt.Println()
 	fmt.Println("help\t\tPrint this message.")
 	fmt.Println()
 	fmt.Println("list\t\tCrawl a list of URLs provided on stdin.")
 	fmt.Println()
-	fmt.Println("\t\tThe -format={text|xml} flag determines the expected type.")
+	fmt.Println("\t\tThe -format={text|txt-sitemap|auto|xml|rss|atom|csv} flag")
+	fmt.Println("\t\tdetermines the expected type; txt-sitemap is another name")
+	fmt.Println("\t\tfor text. With -format=auto, it is detected. Input")
+	fmt.Println("\t\tmay be gzip-compressed. The -column flag selects the CSV")
+	fmt.Println("\t\tcolumn of URLs, by number or header.")
 	fmt.Println()
 	fmt.Println("\t\tExample:")
 	fmt.Println("\t\tcrawl list config.json <url_list.txt >out.txt")
 	fmt.Println("\t\tcrawl list -format=xml config.json <sitemap.xml >out.txt")
+	fmt.Println("\t\tcrawl list -format=csv -column=Address config.json <export.csv >out.txt")
+	fmt.Println()
+	fmt.Println("mksitemap\tWrite sitemaps and a sitemap index listing the indexable")
+	fmt.Println("\t\tpages in crawl output provided on stdin. Pages are listed")
+	fmt.Println("\t\tonly if they return 200, aren't noindex, and aren't")
+	fmt.Println("\t\tcanonicalized elsewhere. Their lastmod is taken from the")
+	fmt.Println("\t\tLast-Modified header. Sitemaps are split at protocol limits.")
+	fmt.Println()
+	fmt.Println("\t\tThe -dir flag sets the directory to write files to, and -name")
+	fmt.Println("\t\tthe prefix of their names (default sitemap). The -base flag")
+	fmt.Println("\t\tsets the URL the files will be published under (default the")
+	fmt.Println("\t\troot of the site of the first page). Only pages under it are")
+	fmt.Println("\t\tlisted. The -gzip flag compresses them.")
+	fmt.Println()
+	fmt.Println("\t\tExample:")
+	fmt.Println("\t\tcrawl mksitemap -gzip -dir=public <out.txt")
+	fmt.Println()
+	fmt.Println("robots\t\tTest URLs against a robots.txt file, given by the URL of")
+	fmt.Println("\t\ta page or robots.txt file, or by the location of a draft file.")
+	fmt.Println("\t\tThe URLs to test follow as arguments, or are read from stdin")
+	fmt.Println("\t\tas a list or as crawl output. For each URL and user-agent,")
+	fmt.Println("\t\tit prints whether the URL is allowed, and the group and rule")
+	fmt.Println("\t\tthat decided. If the rules don't explain the decision, it")
+	fmt.Println("\t\tsays so with Unexplained instead.")
+	fmt.Println()
+	fmt.Println("\t\tThe -ua flag sets the comma-separated user-agents to test")
+	fmt.Println("\t\t(default Crawler). With a draft file, the -live flag also")
+	fmt.Println("\t\ttests the live robots.txt of each URL and reports changes.")
+	fmt.Println()
+	fmt.Println("\t\tExample:")
+	fmt.Println("\t\tcrawl robots -ua=Googlebot https://www.example.com/private/")
+	fmt.Println("\t\tcrawl robots -live draft-robots.txt <out.txt >robots.txt")
 	fmt.Println()
 	fmt.Println("schema\t\tPrint a BigQuery-compatible JSON schema to stdout.")
 	fmt.Println()
+	fmt.Println("\t\tThe -type={result|finding} flag selects the output described:")
+	fmt.Println("\t\tcrawl data, or the findings of crawl sitemap -validate.")
+	fmt.Println()
 	fmt.Println("\t\tExample:")
 	fmt.Println("\t\tcrawl schema >schema.json")
+	fmt.Println("\t\tcrawl schema -type=finding >finding-schema.json")
+	fmt.Println()
+	fmt.Println("sitemap\t\tRecursively requests the sitemaps or sitemap indexes at")
+	fmt.Println("\t\tthe URLs provided as arguments. If none is provided, the")
+	fmt.Println("\t\tsitemaps listed in robots.txt or at common locations are")
+	fmt.Println("\t\tfound for each site in From. The sitemap metadata of each")
+	fmt.Println("\t\tURL is recorded in the SitemapEntry field of its result.")
+	fmt.Println("\t\tSitemaps and indexes may be gzip-compressed. They are")
+	fmt.Println("\t\trequested with the UserAgent and Header of the configuration.")
+	fmt.Println()
+	fmt.Println("\t\tThe -maxdepth flag limits the nesting of indexes, and the")
+	fmt.Println("\t\t-maxurls flag the number of URLs crawled. They override")
+	fmt.Println("\t\tSitemapMaxDepth and SitemapMaxURLs; -1 means no limit.")
 	fmt.Println()
-	fmt.Println("sitemap\t\tRecursively requests a sitemap or sitemap index from")
-	fmt.Println("\t\ta URL provided as argument.")
+	fmt.Println("\t\tThe -validate flag prints one finding per problem with the")
+	fmt.Println("\t\tsitemaps instead of crawl data: protocol limits, empty")
+	fmt.Println("\t\tsitemaps, invalid values, duplicates, URLs on other hosts,")
+	fmt.Println("\t\tand URLs that aren't indexable when crawled.")
 	fmt.Println()
 	fmt.Println("\t\tExample:")
-	fmt.Println("\t\tcrawl sitemap http://www.example.com/sitemap.xml >out.txt")
+	fmt.Println("\t\tcrawl sitemap config.json http://www.example.com/sitemap.xml >out.txt")
+	fmt.Println("\t\tcrawl sitemap config.json >out.txt")
+	fmt.Println("\t\tcrawl sitemap -validate config.json >findings.txt")
 	fmt.Println()
 	fmt.Println("spider\t\tCrawl from the URLs specific in the configuration file.")
 	fmt.Println()
@@ -3,6 +3,8 @@ package crawler
 import (
 	"os"
 	"testing"
+
+	"github.com/benjaminestes/crawl/sitemap"
 )
 
 func TestIllFormed(t *testing.T) {
@@ -35,3 +37,21 @@ func TestBadWait(t *testing.T) {
 		t.Fatalf("invalid config should trigger error on start")
 	}
 }
+
+func TestSitemapFetcherLimits(t *testing.T) {
+	for _, tt := range []struct {
+		maxDepth, maxURLs   int
+		wantDepth, wantURLs int
+	}{
+		{0, 0, sitemap.DefaultMaxDepth, sitemap.DefaultMaxURLs},
+		{2, 10, 2, 10},
+		{-1, -1, 0, 0},
+	} {
+		c := &Crawler{SitemapMaxDepth: tt.maxDepth, SitemapMaxURLs: tt.maxURLs}
+		f := c.SitemapFetcher()
+		if f.MaxDepth != tt.wantDepth || f.MaxURLs != tt.wantURLs {
+			t.Errorf("limits %d, %d: expected fetcher limits %d, %d, got %d, %d",
+				tt.maxDepth, tt.maxURLs, tt.wantDepth, tt.wantURLs, f.MaxDepth, f.MaxURLs)
+		}
+	}
+}
@@ -7,6 +7,9 @@
 package crawler
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -14,6 +17,7 @@ import (
 	"time"
 
 	"github.com/benjaminestes/crawl/crawler/data"
+	"github.com/benjaminestes/crawl/sitemap"
 	"github.com/benjaminestes/robots"
 )
 
@@ -26,20 +30,30 @@ func (u resolvedURL) String() string {
 func (c *Crawler) initialQueue() ([]resolvedURL, error) {
 	var result []resolvedURL
 	for _, s := range c.From {
-		u, err := url.Parse(s)
+		addr, err := resolve(s)
 		if err != nil {
 			return nil, err
 		}
-		// Per RFC 1945, a request without a path part must
-		// send a "/".
-		if u.Path == "" {
-			u.Path = "/"
-		}
-		result = append(result, resolvedURL(u.String()))
+		result = append(result, addr)
 	}
 	return result, nil
 }
 
+// resolve puts a URL from the configuration in the form in which it
+// will be requested.
+func resolve(rawurl string) (resolvedURL, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return "", err
+	}
+	// Per RFC 1945, a request without a path part must send a
+	// "/".
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return resolvedURL(u.String()), nil
+}
+
 type Crawler struct {
 	// Exported configuration fields.
 	Connections     int
@@ -53,14 +67,72 @@ type Crawler struct {
 	WaitTime        string
 	Header          []*data.Pair
 
+	// FetchResources is the HTTP method ("HEAD" or "GET") used to
+	// request the images, scripts and other resources found on
+	// each page. If it is empty, resources are not requested.
+	FetchResources string
+
+	// MinHashSize is the number of values in the MinHash
+	// signature recorded for each page. If it is 0, only a
+	// SimHash is recorded.
+	MinHashSize int
+
+	// StoreMainText says whether the text of the main content of
+	// each page is included in its result.
+	StoreMainText bool
+
+	// RespectPageNofollow says whether to ignore all links on
+	// pages with a nofollow robots meta tag or X-Robots-Tag
+	// header. SkipNoindexLinks likewise ignores links on noindex
+	// pages. A URL linked from such a page will still be crawled
+	// if another page links to it.
+	RespectPageNofollow bool
+	SkipNoindexLinks    bool
+
+	// FollowClientRedirects says whether the targets of meta
+	// refresh and JavaScript redirects are crawled, like the
+	// targets of HTTP redirects.
+	FollowClientRedirects bool
+
+	// SniffContent says whether to infer the type of a response
+	// from its content, if its Content-Type header is missing or
+	// names a type the crawler can't extract data from.
+	SniffContent bool
+
+	// CrawlSitemaps says whether to crawl the URLs listed in the
+	// sitemaps of each site in From, along with From itself, so
+	// that pages without links to them are crawled too.
+	CrawlSitemaps bool
+
+	// SitemapMaxDepth is the maximum nesting of the sitemap
+	// indexes read to seed a crawl, and SitemapMaxURLs is the
+	// maximum number of URLs read from them. If they are 0,
+	// sitemap.DefaultMaxDepth and sitemap.DefaultMaxURLs are used;
+	// if they are negative, there is no limit.
+	SitemapMaxDepth int
+	SitemapMaxURLs  int
+
+	// SitemapEntries maps URLs in From to the sitemap metadata to
+	// be recorded with their results. It is set by callers that
+	// read From from sitemaps, not by configuration.
+	SitemapEntries map[string]*data.SitemapEntry `json:"-"`
+
 	depth   int
 	queue   []resolvedURL
 	seen    map[resolvedURL]bool
 	results chan *data.Result
 
+	// discovery records how each URL in seen was found. Like
+	// seen, it is guarded by mu once the crawl has begun.
+	discovery map[resolvedURL]string
+
 	// robots maintains a robots.txt matcher for every encountered
 	// domain
-	robots map[string]func(string) bool
+	robots map[string]*robotsFetch
+
+	// robotsMu guards robots, since resources are checked against
+	// robots.txt by concurrent fetches.
+	robotsMu sync.Mutex
 
 	// mu guards nextqueue when multiple fetches may try to write
 	// to it simultaneously
@@ -75,9 +147,12 @@ type Crawler struct {
 	// Config.Connections connections are active
 	connections chan bool
 
-	// wait is the parsed version of Config.WaitTime
+	// wait is the parsed version of Config.WaitTime. wmu guards
+	// lastRequestTime, since resources are requested by
+	// concurrent fetches.
 	wait            time.Duration
 	lastRequestTime time.Time
+	wmu             sync.Mutex
 
 	// (in|ex)clude are the compiled versions of
 	// Config.(In|Ex)clude, which are []string.
@@ -85,6 +160,18 @@ type Crawler struct {
 	exclude []*regexp.Regexp
 
 	client *http.Client
+
+	// options are passed on to data.MakeResult
+	options *data.Options
+
+	// sitemapEntries is SitemapEntries keyed by the form of each
+	// URL in the queue.
+	sitemapEntries map[resolvedURL]*data.SitemapEntry
+
+	// resources caches the response to every requested resource,
+	// since most are shared between many pages. rmu guards it.
+	resources map[string]*resourceFetch
+	rmu       sync.Mutex
 }
 
 // initializeClient uses a config object to create an http.Client
@@ -127,6 +214,12 @@ func (c *Crawler) Start() error {
 		return err
 	}
 
+	switch c.FetchResources {
+	case "", "HEAD", "GET":
+	default:
+		return fmt.Errorf("FetchResources must be \"HEAD\", \"GET\" or empty, not %q", c.FetchResources)
+	}
+
 	conns := c.Connections
 	if conns < 1 {
 		conns = 1
@@ -134,13 +227,28 @@ func (c *Crawler) Start() error {
 
 	c.client = initializedClient(c)
 	c.connections = make(chan bool, conns)
+	c.discovery = make(map[resolvedURL]string)
 	c.exclude = preparePattern(c.Exclude)
 	c.include = preparePattern(c.Include)
+	c.options = &data.Options{
+		MinHashSize:     c.MinHashSize,
+		RobotsUserAgent: c.RobotsUserAgent,
+		SniffContent:    c.SniffContent,
+		StoreMainText:   c.StoreMainText,
+	}
 	c.queue = queue
-	c.robots = make(map[string]func(string) bool)
+	c.resources = make(map[string]*resourceFetch)
+	c.robots = make(map[string]*robotsFetch)
 	c.seen = make(map[resolvedURL]bool)
+	c.sitemapEntries = make(map[resolvedURL]*data.SitemapEntry)
 	c.wait = wait
 
+	for rawurl, entry := range c.SitemapEntries {
+		if addr, err := resolve(rawurl); err == nil {
+			c.sitemapEntries[addr] = entry
+		}
+	}
+
 	// If a URL has not been seen when the crawler processes a
 	// link, that URL will be added to the next queue to crawl. It
 	// does not impact whether a URL in the current queue will be
@@ -149,11 +257,15 @@ func (c *Crawler) Start() error {
 	// starts.
 	for _, addr := range c.queue {
 		c.seen[addr] = true
+		c.discovery[addr] = data.DiscoveryStart
+		if _, ok := c.sitemapEntries[addr]; ok {
+			c.discovery[addr] = data.DiscoverySitemap
+		}
 	}
 
 	c.results = make(chan *data.Result, conns)
 	go func() {
-		for f := crawlStartQueue; f != nil; f = f(c) {
+		for f := crawlSeedSitemaps; f != nil; f = f(c) {
 		}
 		close(c.results)
 	}()
@@ -201,31 +313,96 @@ func (c *Crawler) willCrawl(fullurl resolvedURL) bool {
 	return true
 }
 
-// addRobots creates a robots.txt matcher from a URL string. If there
-// is a problem reading from robots.txt, treat it as a server error.
-func (c *Crawler) addRobots(fullurl string) {
-	rtxtURL, err := robots.Locate(fullurl)
+// InScope says whether rawurl is in the scope of the crawl, as
+// defined by Include and Exclude. It must not be called while the
+// crawl is running.
+func (c *Crawler) InScope(rawurl string) bool {
+	addr, err := resolve(rawurl)
 	if err != nil {
-		// Error parsing fullurl.
-		return
+		return false
 	}
+	if c.include == nil {
+		c.include = preparePattern(c.Include)
+	}
+	if c.exclude == nil {
+		c.exclude = preparePattern(c.Exclude)
+	}
+	return c.willCrawl(addr)
+}
+
+// robotsTimeout is the longest a request for robots.txt may take.
+const robotsTimeout = 30 * time.Second
 
-	resp, err := http.Get(rtxtURL)
+// robotsClient requests robots.txt files. Unlike the client used for
+// pages, it follows redirects.
+var robotsClient = &http.Client{Timeout: robotsTimeout}
+
+// robotsFetch is the robots.txt file of a site, which is complete
+// once done is closed. test is its matcher, and sitemaps the URLs on
+// its Sitemap lines.
+type robotsFetch struct {
+	done     chan struct{}
+	test     func(string) bool
+	sitemaps []string
+}
+
+// addRobots reads the robots.txt file at rtxtURL into f. If there is
+// a problem reading from robots.txt, treat it as a server error.
+func (c *Crawler) addRobots(rtxtURL string, f *robotsFetch) {
+	unavailable, _ := robots.From(503, nil)
+	f.test = unavailable.Tester(c.RobotsUserAgent)
+
+	resp, err := robotsClient.Get(rtxtURL)
 	if err != nil {
-		rtxt, _ := robots.From(503, nil)
-		c.robots[rtxtURL] = rtxt.Tester(c.RobotsUserAgent)
 		return
 	}
 	defer resp.Body.Close()
 
-	rtxt, err := robots.From(resp.StatusCode, resp.Body)
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	rtxt, err := robots.From(resp.StatusCode, bytes.NewReader(body))
 	if err != nil {
-		rtxt, _ := robots.From(503, nil)
-		c.robots[rtxtURL] = rtxt.Tester(c.RobotsUserAgent)
 		return
 	}
 
-	c.robots[rtxtURL] = rtxt.Tester(c.RobotsUserAgent)
+	f.test = rtxt.Tester(c.RobotsUserAgent)
+	if resp.StatusCode == http.StatusOK {
+		f.sitemaps = sitemap.ParseRobots(bytes.NewReader(body))
+	}
+}
+
+// siteRobots returns the robots.txt file at rtxtURL, requesting it
+// first if it hasn't been. Each robots.txt file is requested once: a
+// fetch needing one that another is already requesting waits for it,
+// but fetches for other sites don't.
+func (c *Crawler) siteRobots(rtxtURL string) *robotsFetch {
+	c.robotsMu.Lock()
+	f, ok := c.robots[rtxtURL]
+	if !ok {
+		f = &robotsFetch{done: make(chan struct{})}
+		c.robots[rtxtURL] = f
+	}
+	c.robotsMu.Unlock()
+
+	if ok {
+		<-f.done
+	} else {
+		c.addRobots(rtxtURL, f)
+		close(f.done)
+	}
+	return f
+}
+
+// robotsAllow says whether robots.txt allows fullurl to be
+// requested.
+func (c *Crawler) robotsAllow(fullurl string) (bool, error) {
+	rtxtURL, err := robots.Locate(fullurl)
+	if err != nil {
+		return false, err
+	}
+	return c.siteRobots(rtxtURL).test(fullurl), nil
 }
 
 // Returns the next result from the crawl. Results are guaranteed to come
@@ -244,14 +421,41 @@ func (c *Crawler) Next() *data.Result {
 
 // resetWait sets the last time the crawler spawned a request.
 func (c *Crawler) resetWait() {
+	c.wmu.Lock()
 	c.lastRequestTime = time.Now()
+	c.wmu.Unlock()
+}
+
+// sinceLastRequest is the time since the crawler last spawned a
+// request.
+func (c *Crawler) sinceLastRequest() time.Duration {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	return time.Since(c.lastRequestTime)
+}
+
+// awaitTurn blocks until c.WaitTime has elapsed since the last
+// request, then resets the wait. It is used by concurrent fetches,
+// where the state machine uses crawlWait.
+func (c *Crawler) awaitTurn() {
+	for {
+		c.wmu.Lock()
+		d := c.wait - time.Since(c.lastRequestTime)
+		if d <= 0 {
+			c.lastRequestTime = time.Now()
+			c.wmu.Unlock()
+			return
+		}
+		c.wmu.Unlock()
+		time.Sleep(d)
+	}
 }
 
 // merge takes a []*data.Link and adds it to the next queue to be
 // crawled.  In other words, it assembles the URLs that represent the
 // next level of the crawl. Many merges could be simultaneously
-// active.
-func (c *Crawler) merge(links []*data.Link) {
+// active. how says how the links were discovered.
+func (c *Crawler) merge(links []*data.Link, how string) {
 	// This is how the crawler terminates — it will encounter an
 	// empty queue if no URLs have been added to the next queue.
 	if !(c.depth < c.MaxDepth) {
@@ -275,6 +479,7 @@ func (c *Crawler) merge(links []*data.Link) {
 		if _, ok := c.seen[linkURL]; !ok {
 			if !(link.Nofollow && c.RespectNofollow) {
 				c.seen[linkURL] = true
+				c.discovery[linkURL] = how
 				c.nextqueue = append(c.nextqueue, linkURL)
 			}
 		}
@@ -282,6 +487,31 @@ func (c *Crawler) merge(links []*data.Link) {
 	}
 }
 
+// followLinks says whether the links found on the page described by
+// result should be crawled, given its page-level robots directives.
+func (c *Crawler) followLinks(result *data.Result) bool {
+	ix := result.Indexability
+	if ix == nil {
+		return true
+	}
+	if c.RespectPageNofollow && ix.Nofollow {
+		return false
+	}
+	if c.SkipNoindexLinks && ix.Noindex {
+		return false
+	}
+	return true
+}
+
+// annotate records what the crawler knows about addr, aside from its
+// response, in result.
+func (c *Crawler) annotate(result *data.Result, addr resolvedURL) {
+	result.SitemapEntry = c.sitemapEntries[addr]
+	c.mu.Lock()
+	result.Discovery = c.discovery[addr]
+	c.mu.Unlock()
+}
+
 // fetch requests a URL, hydrates a result object based on its
 // contents, if any, and initiates a merge of the links discovered in
 // the process.
@@ -300,16 +530,33 @@ func (c *Crawler) fetch(addr resolvedURL) {
 		}
 	}
 
-	result := data.MakeResult(addr.String(), c.depth, resp)
+	result := data.MakeResult(addr.String(), c.depth, resp, c.options)
+	c.annotate(result, addr)
 
 	if resp != nil && resp.StatusCode >= 300 && resp.StatusCode < 400 {
 		c.merge([]*data.Link{
 			&data.Link{
 				Address: result.ResolvesTo,
 			},
-		})
+		}, data.DiscoveryRedirect)
+	}
+
+	if c.FollowClientRedirects {
+		var targets []*data.Link
+		for _, r := range result.ClientRedirects {
+			targets = append(targets, &data.Link{
+				Address: r.Address,
+			})
+		}
+		c.merge(targets, data.DiscoveryClientRedirect)
+	}
+
+	if c.FetchResources != "" {
+		c.fetchResources(result.Resources)
 	}
 
-	c.merge(result.Links)
+	if c.followLinks(result) {
+		c.merge(result.Links, data.DiscoveryLink)
+	}
 	c.results <- result
 }
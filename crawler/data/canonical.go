@@ -1,8 +1,11 @@
 package data
 
+// Canonical is a canonical URL declared by a page. Source is "html"
+// for a <link> element, or "header" for an HTTP Link header.
 type Canonical struct {
 	Address *Address
 	Href    string
+	Source  string
 }
 
 func MakeCanonical(base *Address, href string) *Canonical {
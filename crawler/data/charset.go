@@ -0,0 +1,87 @@
+package data
+
+import (
+	"bytes"
+	"mime"
+	"strings"
+
+	"github.com/benjaminestes/crawl/scrape"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// Charset describes the character encoding of a document. Header is
+// the charset given in the Content-Type header, Meta the one given
+// by a meta tag, and BOM the one implied by a byte order mark.
+// Detected is the encoding the document was decoded from. Names are
+// the canonical names of the WHATWG Encoding Standard, where known.
+type Charset struct {
+	Header   string
+	Meta     string
+	BOM      string
+	Detected string
+}
+
+var boms = []struct {
+	bom  []byte
+	name string
+}{
+	{[]byte{0xef, 0xbb, 0xbf}, "utf-8"},
+	{[]byte{0xfe, 0xff}, "utf-16be"},
+	{[]byte{0xff, 0xfe}, "utf-16le"},
+}
+
+// decodeBody determines the encoding of body, which was served with
+// the given Content-Type, and returns body transcoded to UTF-8.
+func decodeBody(body []byte, contentType string) ([]byte, *Charset) {
+	cs := &Charset{}
+
+	if _, params, err := mime.ParseMediaType(contentType); err == nil {
+		cs.Header = charsetName(params["charset"])
+	}
+	for _, b := range boms {
+		if bytes.HasPrefix(body, b.bom) {
+			cs.BOM = b.name
+			break
+		}
+	}
+
+	e, name, _ := charset.DetermineEncoding(body, contentType)
+	cs.Detected = name
+
+	decoded, err := e.NewDecoder().Bytes(body)
+	if err != nil {
+		return body, cs
+	}
+	return decoded, cs
+}
+
+// getMetaCharset returns the charset declared by a meta tag in n,
+// either <meta charset> or <meta http-equiv="Content-Type">.
+func getMetaCharset(n *html.Node) string {
+	for _, meta := range scrape.NodesByTagName("meta", n) {
+		if scrape.HasAttribute("charset", meta) {
+			return charsetName(scrape.Attribute("charset", meta))
+		}
+		if strings.EqualFold(scrape.Attribute("http-equiv", meta), "content-type") {
+			_, params, err := mime.ParseMediaType(scrape.Attribute("content", meta))
+			if err == nil && params["charset"] != "" {
+				return charsetName(params["charset"])
+			}
+		}
+	}
+	return ""
+}
+
+// charsetName returns the canonical name of the encoding label, or
+// the label itself if it is not recognized.
+func charsetName(label string) string {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return ""
+	}
+	if _, name := charset.Lookup(label); name != "" {
+		return name
+	}
+	return strings.ToLower(label)
+}
@@ -0,0 +1,74 @@
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// A contentHandler extracts data from the body of a response of a
+// particular media type into r. Handlers for HTML-like types return
+// the parsed document, so that its robots meta tags can be read.
+type contentHandler func(r *Result, body io.Reader, header http.Header, opts *Options) (*html.Node, error)
+
+// contentHandlers maps media types to the handler for their content.
+// Responses of other types are recorded, but their content is not
+// read.
+var contentHandlers = map[string]contentHandler{
+	"text/html":             hydrateHTML,
+	"application/xhtml+xml": hydrateHTML,
+	"application/pdf":       hydratePDF,
+}
+
+// sniffLen is the number of bytes examined to sniff a media type, as
+// in http.DetectContentType.
+const sniffLen = 512
+
+// hydrateContent dispatches the body of resp to the handler for its
+// media type. If the declared type has no handler, and sniffing is
+// enabled, the type is instead inferred from the content.
+func hydrateContent(r *Result, resp *http.Response, opts *Options) (*html.Node, error) {
+	body := bufio.NewReaderSize(resp.Body, sniffLen)
+
+	r.MediaType = mediaType(resp.Header.Get("Content-Type"))
+	handler, ok := contentHandlers[r.MediaType]
+	if !ok && opts.SniffContent {
+		head, _ := body.Peek(sniffLen)
+		if sniffed := sniffMediaType(head); sniffed != r.MediaType {
+			if handler, ok = contentHandlers[sniffed]; ok {
+				r.MediaType = sniffed
+				r.MediaTypeSniffed = true
+			}
+		}
+	}
+	if !ok {
+		return nil, nil
+	}
+	return handler(r, body, resp.Header, opts)
+}
+
+// mediaType returns the lower-cased media type of a Content-Type
+// header, without parameters.
+func mediaType(contentType string) string {
+	t, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		// Fall back to whatever precedes the parameters.
+		t = strings.TrimSpace(strings.SplitN(contentType, ";", 2)[0])
+	}
+	return strings.ToLower(t)
+}
+
+// sniffMediaType infers the media type of content from its first
+// bytes. It extends http.DetectContentType by recognizing XHTML.
+func sniffMediaType(head []byte) string {
+	t := mediaType(http.DetectContentType(head))
+	if t == "text/xml" && bytes.Contains(bytes.ToLower(head), []byte("<html")) {
+		return "application/xhtml+xml"
+	}
+	return t
+}
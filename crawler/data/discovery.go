@@ -0,0 +1,11 @@
+package data
+
+// These are the ways the crawler may discover a URL, as recorded in
+// Result.Discovery.
+const (
+	DiscoveryStart          = "start"
+	DiscoverySitemap        = "sitemap"
+	DiscoveryLink           = "link"
+	DiscoveryRedirect       = "redirect"
+	DiscoveryClientRedirect = "client redirect"
+)
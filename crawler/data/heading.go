@@ -0,0 +1,37 @@
+package data
+
+// Heading is one entry in the outline of a page. Order is the
+// position of the heading among all h1-h6 elements on the page.
+type Heading struct {
+	Level int
+	Text  string
+	Order int
+}
+
+// HeadingCounts records how many headings of each level appear on a
+// page.
+type HeadingCounts struct {
+	H1 int
+	H2 int
+	H3 int
+	H4 int
+	H5 int
+	H6 int
+}
+
+func (hc *HeadingCounts) add(level int) {
+	switch level {
+	case 1:
+		hc.H1++
+	case 2:
+		hc.H2++
+	case 3:
+		hc.H3++
+	case 4:
+		hc.H4++
+	case 5:
+		hc.H5++
+	case 6:
+		hc.H6++
+	}
+}
@@ -1,9 +1,13 @@
 package data
 
+// Hreflang is an alternate version of a page in another language.
+// Source is "html" for a <link> element, "header" for an HTTP Link
+// header, or "sitemap" for an xhtml:link element in a sitemap.
 type Hreflang struct {
 	Address  *Address
 	Href     string
 	Hreflang string
+	Source   string
 }
 
 func MakeHreflang(base *Address, href, lang string) *Hreflang {
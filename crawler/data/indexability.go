@@ -0,0 +1,191 @@
+package data
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/benjaminestes/crawl/scrape"
+	"golang.org/x/net/html"
+)
+
+// Indexability combines the robots directives of a page that apply
+// to the crawler's robots user-agent: those addressed to all robots,
+// and those addressed to that user-agent by name. Every directive
+// found, whatever its scope, is listed in Directives.
+type Indexability struct {
+	Noindex          bool
+	Nofollow         bool
+	Noarchive        bool
+	Nosnippet        bool
+	MaxSnippet       string
+	UnavailableAfter string
+	Directives       []*RobotsDirective `json:",omitempty"`
+}
+
+// RobotsDirective is a set of robots directives from a single
+// source: a meta tag ("meta") or an X-Robots-Tag header ("header").
+// UserAgent is empty if the directives apply to all robots.
+type RobotsDirective struct {
+	Source           string
+	UserAgent        string
+	Content          string
+	Noindex          bool
+	Nofollow         bool
+	Noarchive        bool
+	Nosnippet        bool
+	MaxSnippet       string
+	UnavailableAfter string
+}
+
+// robotsMetaNames are the names of meta tags, other than "robots",
+// that major search engines read robots directives from.
+var robotsMetaNames = map[string]bool{
+	"googlebot":       true,
+	"googlebot-news":  true,
+	"googlebot-image": true,
+	"bingbot":         true,
+	"msnbot":          true,
+	"slurp":           true,
+	"yandex":          true,
+	"baiduspider":     true,
+	"duckduckbot":     true,
+}
+
+// robotsDirectiveNames lists the directives that may appear in a
+// robots meta tag or header. A name followed by a colon that is not
+// in this set is taken to be a user-agent.
+var robotsDirectiveNames = map[string]bool{
+	"all":               true,
+	"none":              true,
+	"index":             true,
+	"noindex":           true,
+	"follow":            true,
+	"nofollow":          true,
+	"noarchive":         true,
+	"nocache":           true,
+	"nosnippet":         true,
+	"noimageindex":      true,
+	"notranslate":       true,
+	"indexifembedded":   true,
+	"max-snippet":       true,
+	"max-image-preview": true,
+	"max-video-preview": true,
+	"unavailable_after": true,
+}
+
+func getIndexability(doc *html.Node, header http.Header, ua string) *Indexability {
+	var directives []*RobotsDirective
+	if doc != nil {
+		for _, meta := range scrape.NodesByTagName("meta", doc) {
+			name := strings.ToLower(strings.TrimSpace(scrape.Attribute("name", meta)))
+			content := scrape.Attribute("content", meta)
+			switch {
+			case name == "robots":
+				directives = append(directives, parseRobotsDirectives("meta", "", content)...)
+			case robotsMetaNames[name] || (name != "" && name == strings.ToLower(ua)):
+				directives = append(directives, parseRobotsDirectives("meta", name, content)...)
+			}
+		}
+	}
+	for _, content := range header[http.CanonicalHeaderKey("X-Robots-Tag")] {
+		directives = append(directives, parseRobotsDirectives("header", "", content)...)
+	}
+
+	ix := &Indexability{Directives: directives}
+	for _, d := range directives {
+		if d.UserAgent != "" && d.UserAgent != strings.ToLower(ua) {
+			continue
+		}
+		ix.Noindex = ix.Noindex || d.Noindex
+		ix.Nofollow = ix.Nofollow || d.Nofollow
+		ix.Noarchive = ix.Noarchive || d.Noarchive
+		ix.Nosnippet = ix.Nosnippet || d.Nosnippet
+		if d.MaxSnippet != "" {
+			ix.MaxSnippet = d.MaxSnippet
+		}
+		if d.UnavailableAfter != "" {
+			ix.UnavailableAfter = d.UnavailableAfter
+		}
+	}
+	return ix
+}
+
+// parseRobotsDirectives parses the content of a robots meta tag or
+// X-Robots-Tag header. Header values may be prefixed with a
+// user-agent, as in "googlebot: noindex", and may address several
+// user-agents in turn, so one content string can produce several
+// RobotsDirectives.
+func parseRobotsDirectives(source, ua, content string) (directives []*RobotsDirective) {
+	d := &RobotsDirective{Source: source, UserAgent: ua}
+	var last string
+	for _, part := range strings.Split(content, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value := part, ""
+		if i := strings.Index(part, ":"); i >= 0 {
+			name, value = strings.TrimSpace(part[:i]), strings.TrimSpace(part[i+1:])
+		}
+		name = strings.ToLower(name)
+
+		switch {
+		case robotsDirectiveNames[name]:
+			// Handled below.
+		case source == "header" && isRobotsDirective(value):
+			// A new user-agent scope begins.
+			if d.Content != "" {
+				directives = append(directives, d)
+			}
+			d = &RobotsDirective{Source: source, UserAgent: name}
+			part = value
+			name, value = strings.ToLower(value), ""
+			if i := strings.Index(part, ":"); i >= 0 {
+				name, value = strings.ToLower(strings.TrimSpace(part[:i])), strings.TrimSpace(part[i+1:])
+			}
+		case last == "unavailable_after":
+			// Dates may contain commas, as in "Wed, 25 Jun 2025".
+			d.UnavailableAfter += ", " + part
+			d.Content += ", " + part
+			continue
+		}
+
+		if d.Content != "" {
+			d.Content += ", "
+		}
+		d.Content += part
+		last = name
+
+		switch name {
+		case "none":
+			d.Noindex = true
+			d.Nofollow = true
+		case "noindex":
+			d.Noindex = true
+		case "nofollow":
+			d.Nofollow = true
+		case "noarchive", "nocache":
+			d.Noarchive = true
+		case "nosnippet":
+			d.Nosnippet = true
+		case "max-snippet":
+			d.MaxSnippet = value
+		case "unavailable_after":
+			d.UnavailableAfter = value
+		}
+	}
+	if d.Content != "" {
+		directives = append(directives, d)
+	}
+	return
+}
+
+// isRobotsDirective reports whether s begins with the name of a
+// robots directive.
+func isRobotsDirective(s string) bool {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = strings.TrimSpace(name[:i])
+	}
+	return robotsDirectiveNames[name]
+}
@@ -0,0 +1,52 @@
+package data
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestIndexability(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><head>
+<meta name="robots" content="noarchive, max-snippet:50">
+<meta name="googlebot" content="noindex">
+<meta name="crawler" content="nosnippet">
+</head></html>`))
+	if err != nil {
+		t.Fatalf("couldn't parse test data")
+	}
+	header := http.Header{}
+	header.Add("X-Robots-Tag", "otherbot: nofollow, unavailable_after: Wed, 25 Jun 2025 15:00:00 GMT")
+
+	ix := getIndexability(doc, header, "Crawler")
+
+	if len(ix.Directives) != 4 {
+		t.Fatalf("expected 4 directives, got %d", len(ix.Directives))
+	}
+	if ix.Noindex || ix.Nofollow || !ix.Noarchive || !ix.Nosnippet || ix.MaxSnippet != "50" {
+		t.Errorf("unexpected combined directives %+v", ix)
+	}
+	if d := ix.Directives[3]; d.UserAgent != "otherbot" || !d.Nofollow || d.UnavailableAfter != "Wed, 25 Jun 2025 15:00:00 GMT" {
+		t.Errorf("unexpected header directive %+v", d)
+	}
+}
+
+func TestIndexable(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "application/pdf")
+	header.Set("Link", `</other.pdf>; rel=canonical`)
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+	}
+
+	r := MakeResult("http://example.com/doc.pdf", 0, resp, nil)
+
+	if r.Indexable || len(r.NonIndexableReasons) != 1 || r.NonIndexableReasons[0] != ReasonCanonicalized {
+		t.Errorf("expected page canonicalized by header, got %v", r.NonIndexableReasons)
+	}
+}
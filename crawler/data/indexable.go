@@ -0,0 +1,64 @@
+package data
+
+// These are the reasons a page may be considered not indexable, as
+// listed in Result.NonIndexableReasons.
+const (
+	ReasonBlocked       = "blocked by robots.txt"
+	ReasonNoResponse    = "no response"
+	ReasonRedirect      = "redirect"
+	ReasonStatus        = "non-200 status"
+	ReasonNoindex       = "noindex"
+	ReasonCanonicalized = "canonicalized"
+)
+
+// MakeBlockedResult returns a result for a URL that was not requested
+// because robots.txt disallows it.
+func MakeBlockedResult(rawurl string, depth int, opts *Options) *Result {
+	result := MakeResult(rawurl, depth, nil, opts)
+	result.Status = "Blocked by robots.txt"
+	result.NonIndexableReasons = []string{ReasonBlocked}
+	return result
+}
+
+// setIndexable decides whether a search engine could index the page
+// described by r, and if not, records why.
+func (r *Result) setIndexable() {
+	var reasons []string
+
+	switch {
+	case r.StatusCode == 0:
+		reasons = append(reasons, ReasonNoResponse)
+	case r.StatusCode >= 300 && r.StatusCode < 400:
+		reasons = append(reasons, ReasonRedirect)
+	case r.StatusCode != 200:
+		reasons = append(reasons, ReasonStatus)
+	}
+
+	if r.Indexability != nil && r.Indexability.Noindex {
+		reasons = append(reasons, ReasonNoindex)
+	}
+
+	if r.canonicalizedElsewhere() {
+		reasons = append(reasons, ReasonCanonicalized)
+	}
+
+	r.Indexable = len(reasons) == 0
+	r.NonIndexableReasons = reasons
+}
+
+// canonicalizedElsewhere reports whether the page declares, in HTML
+// or in a Link header, a canonical URL other than its own.
+func (r *Result) canonicalizedElsewhere() bool {
+	if r.Address == nil {
+		return false
+	}
+	if r.Canonical != nil && r.Canonical.Address != nil && r.Canonical.Address.Full != r.Address.Full {
+		return true
+	}
+	for _, c := range r.Canonicals {
+		if c.Address != nil && c.Address.Full != r.Address.Full {
+			return true
+		}
+	}
+	return false
+}
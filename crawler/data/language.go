@@ -0,0 +1,66 @@
+package data
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/benjaminestes/crawl/lang"
+	"github.com/benjaminestes/crawl/scrape"
+	"golang.org/x/net/html"
+)
+
+// Language gathers the signals of which language a page is written
+// in. HTMLLang is the lang attribute of the <html> element,
+// ContentLanguage the Content-Language header, and OGLocale the
+// og:locale meta property. Detected is the language guessed from the
+// text of the page, as an ISO 639-1 code, with a Confidence between 0
+// and 1. Mismatch is true if a declared language differs from the
+// detected one. Only declared languages that package lang can detect
+// are compared, since any other would always differ.
+type Language struct {
+	HTMLLang        string
+	ContentLanguage string
+	OGLocale        string
+	Detected        string `json:",omitempty"`
+	Confidence      float64
+	Mismatch        bool
+}
+
+// getLanguage collects the language signals of a document. doc may be
+// nil if the document is not HTML, and text is its text content.
+func getLanguage(doc *html.Node, header http.Header, text string) *Language {
+	l := &Language{
+		ContentLanguage: header.Get("Content-Language"),
+	}
+	if doc != nil {
+		l.HTMLLang = scrape.Attribute("lang", scrape.Query("html", nil, doc))
+		for _, meta := range scrape.NodesByTagName("meta", doc) {
+			if scrape.Attribute("property", meta) == "og:locale" {
+				l.OGLocale = scrape.Attribute("content", meta)
+				break
+			}
+		}
+	}
+	l.Detected, l.Confidence = lang.Detect(text)
+
+	if l.Detected == "" {
+		return l
+	}
+	for _, tag := range []string{l.HTMLLang, l.OGLocale} {
+		if p := lang.Primary(tag); lang.Known(p) && p != l.Detected {
+			l.Mismatch = true
+		}
+	}
+	// Content-Language may list several languages, any of which
+	// may be the one detected.
+	var known, matched bool
+	for _, tag := range strings.Split(l.ContentLanguage, ",") {
+		p := lang.Primary(tag)
+		known = known || lang.Known(p)
+		matched = matched || p == l.Detected
+	}
+	if known && !matched {
+		l.Mismatch = true
+	}
+	return l
+}
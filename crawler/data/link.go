@@ -1,18 +1,46 @@
 package data
 
+import "strings"
+
 type Link struct {
 	Address  *Address
 	Anchor   string
 	Href     string
 	Nofollow bool
+
+	// Rel is the rel attribute of the link, split into
+	// lower-case tokens.
+	Rel      []string
+	Target   string
+	Title    string
+	Hreflang string
+	Type     string
+
+	// Context is the nearest enclosing landmark (nav, header,
+	// footer, aside or main), if any. Path is the chain of
+	// element names from the document root to the link, and
+	// Position is the index of the link among all links on the
+	// page. Together they help separate boilerplate links from
+	// links in content.
+	Context  string
+	Path     string
+	Position int
 }
 
-func MakeLink(base *Address, href string, anchor string, nofollow bool) *Link {
+func MakeLink(base *Address, href string, anchor string, rel string) *Link {
 	link := &Link{
-		Href:     href,
-		Anchor:   anchor,
-		Nofollow: nofollow,
-		Address:  MakeAddressResolved(base, href),
+		Href:    href,
+		Anchor:  anchor,
+		Rel:     strings.Fields(strings.ToLower(rel)),
+		Address: MakeAddressResolved(base, href),
+	}
+	for _, tok := range link.Rel {
+		// Search engines treat ugc and sponsored links as
+		// hints not to follow them, like nofollow.
+		switch tok {
+		case "nofollow", "ugc", "sponsored":
+			link.Nofollow = true
+		}
 	}
 	return link
 }
@@ -0,0 +1,75 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestMakeLinkRel(t *testing.T) {
+	base := MakeAddress("http://example.com/")
+
+	tests := []struct {
+		rel      string
+		tokens   int
+		nofollow bool
+	}{
+		{"", 0, false},
+		{"noopener", 1, false},
+		{"nofollow", 1, true},
+		{"NoFollow Noopener", 2, true},
+		{"ugc", 1, true},
+		{" sponsored\tnoreferrer ", 2, true},
+		{"nofollowed", 1, false},
+	}
+
+	for _, test := range tests {
+		link := MakeLink(base, "/a", "A", test.rel)
+		if len(link.Rel) != test.tokens {
+			t.Errorf("rel %q: expected %d tokens, got %v", test.rel, test.tokens, link.Rel)
+		}
+		if link.Nofollow != test.nofollow {
+			t.Errorf("rel %q: expected nofollow %v, got %v", test.rel, test.nofollow, link.Nofollow)
+		}
+	}
+
+	if link := MakeLink(base, "/a", "A", "NoFollow Noopener"); link.Rel[0] != "nofollow" || link.Rel[1] != "noopener" {
+		t.Errorf("expected lower-case rel tokens, got %v", link.Rel)
+	}
+}
+
+func TestGetLinks(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><body>
+<nav><ul><li><a href="/nav" rel="nofollow">Nav</a></li></ul></nav>
+<div role="main"><p><a href="/content" target="_blank" title="Content" hreflang="de" type="text/html">Content</a></p></div>
+<div role="contentinfo"><main><a href="/nested">Nested</a></main></div>
+<a href="/none">None</a>
+</body></html>`))
+	if err != nil {
+		t.Fatalf("couldn't parse test data")
+	}
+
+	links := getLinks(MakeAddress("http://example.com/"), doc)
+
+	if len(links) != 4 {
+		t.Fatalf("expected 4 links, got %d", len(links))
+	}
+
+	contexts := []string{"nav", "main", "main", ""}
+	for i, link := range links {
+		if link.Context != contexts[i] {
+			t.Errorf("link %d: expected context %q, got %q", i, contexts[i], link.Context)
+		}
+		if link.Position != i {
+			t.Errorf("link %d: expected position %d, got %d", i, i, link.Position)
+		}
+	}
+
+	if l := links[0]; !l.Nofollow || l.Path != "html > body > nav > ul > li > a" {
+		t.Errorf("unexpected link %+v", l)
+	}
+	if l := links[1]; l.Target != "_blank" || l.Title != "Content" || l.Hreflang != "de" || l.Type != "text/html" || l.Nofollow {
+		t.Errorf("unexpected link %+v", l)
+	}
+}
@@ -0,0 +1,138 @@
+package data
+
+import (
+	"net/http"
+	"strings"
+)
+
+// linkValue is a single link from an HTTP Link header, as described
+// in RFC 8288. Parameter names are lower-cased.
+type linkValue struct {
+	Target string
+	Params map[string]string
+}
+
+// rels returns the link relation types of l, lower-cased.
+func (l *linkValue) rels() []string {
+	return strings.Fields(strings.ToLower(l.Params["rel"]))
+}
+
+// hasRel reports whether l has the relation type rel.
+func (l *linkValue) hasRel(rel string) bool {
+	for _, r := range l.rels() {
+		if r == rel {
+			return true
+		}
+	}
+	return false
+}
+
+// parseLinkHeader returns the links in all Link fields of header.
+// Malformed links are skipped.
+func parseLinkHeader(header http.Header) (links []*linkValue) {
+	for _, field := range header[http.CanonicalHeaderKey("Link")] {
+		links = append(links, parseLinkField(field)...)
+	}
+	return
+}
+
+func parseLinkField(s string) (links []*linkValue) {
+	for {
+		start := strings.Index(s, "<")
+		if start < 0 {
+			return
+		}
+		end := strings.Index(s[start:], ">")
+		if end < 0 {
+			return
+		}
+		l := &linkValue{
+			Target: strings.TrimSpace(s[start+1 : start+end]),
+			Params: make(map[string]string),
+		}
+		s = s[start+end+1:]
+
+		// Parameters run until a comma outside of quotes.
+		var params []string
+		var b strings.Builder
+		quoted := false
+	scan:
+		for len(s) > 0 {
+			c := s[0]
+			s = s[1:]
+			switch {
+			case c == '"':
+				quoted = !quoted
+			case c == '\\' && quoted && len(s) > 0:
+				b.WriteByte(s[0])
+				s = s[1:]
+			case c == ';' && !quoted:
+				params = append(params, b.String())
+				b.Reset()
+			case c == ',' && !quoted:
+				break scan
+			default:
+				b.WriteByte(c)
+			}
+		}
+		params = append(params, b.String())
+
+		for _, p := range params {
+			k, v := p, ""
+			if i := strings.Index(p, "="); i >= 0 {
+				k, v = p[:i], p[i+1:]
+			}
+			k = strings.ToLower(strings.TrimSpace(k))
+			if k == "" {
+				continue
+			}
+			// Only the first occurrence of a parameter counts.
+			if _, ok := l.Params[k]; !ok {
+				l.Params[k] = strings.TrimSpace(v)
+			}
+		}
+		links = append(links, l)
+	}
+}
+
+// hydrateLinkHeader adds the canonical and alternate language URLs
+// declared in Link headers to those found in HTML, and flags
+// conflicting declarations.
+func hydrateLinkHeader(r *Result, header http.Header) {
+	for _, l := range parseLinkHeader(header) {
+		switch {
+		case l.hasRel("canonical"):
+			c := MakeCanonical(r.Address, l.Target)
+			c.Source = "header"
+			r.Canonicals = append(r.Canonicals, c)
+		case l.hasRel("alternate") && l.Params["hreflang"] != "":
+			h := MakeHreflang(r.Address, l.Target, l.Params["hreflang"])
+			h.Source = "header"
+			r.Hreflang = append(r.Hreflang, h)
+		}
+	}
+
+	if len(r.Canonicals) > 0 && (r.Canonical == nil || r.Canonical.Href == "") {
+		r.Canonical = r.Canonicals[0]
+	}
+
+	canonicals := make(map[string]bool)
+	for _, c := range r.Canonicals {
+		if c.Address != nil {
+			canonicals[c.Address.Full] = true
+		}
+	}
+	r.CanonicalConflict = len(canonicals) > 1
+
+	langs := make(map[string]string)
+	for _, h := range r.Hreflang {
+		if h.Address == nil || h.Hreflang == "" {
+			continue
+		}
+		lang := strings.ToLower(h.Hreflang)
+		if full, ok := langs[lang]; ok && full != h.Address.Full {
+			r.HreflangConflict = true
+		}
+		langs[lang] = h.Address.Full
+	}
+}
@@ -0,0 +1,48 @@
+package data
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestParseLinkHeader(t *testing.T) {
+	header := http.Header{}
+	header.Add("Link", `<http://example.com/a>; rel="canonical", <http://example.com/b, c>; rel="alternate"; hreflang=de`)
+
+	links := parseLinkHeader(header)
+
+	if len(links) != 2 {
+		t.Fatalf("expected 2 links, got %d", len(links))
+	}
+	if l := links[0]; l.Target != "http://example.com/a" || !l.hasRel("canonical") {
+		t.Errorf("unexpected link %+v", l)
+	}
+	if l := links[1]; l.Target != "http://example.com/b, c" || l.Params["hreflang"] != "de" {
+		t.Errorf("unexpected link %+v", l)
+	}
+}
+
+func TestLinkHeaderConflicts(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "text/html")
+	header.Add("Link", `</a>; rel=canonical, </de>; rel=alternate; hreflang=de`)
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body: ioutil.NopCloser(strings.NewReader(`<html><head>
+<link rel="canonical" href="/b">
+<link rel="alternate" hreflang="de" href="/de">
+</head></html>`)),
+	}
+
+	r := MakeResult("http://example.com/a", 0, resp, nil)
+
+	if r.Canonical.Source != "html" || len(r.Canonicals) != 2 || !r.CanonicalConflict {
+		t.Errorf("expected conflicting canonicals, got %+v", r.Canonicals)
+	}
+	if len(r.Hreflang) != 2 || r.Hreflang[1].Source != "header" || r.HreflangConflict {
+		t.Errorf("expected consistent hreflang, got %+v", r.Hreflang)
+	}
+}
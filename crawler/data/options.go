@@ -0,0 +1,23 @@
+package data
+
+// Options controls optional work done while building a Result. A nil
+// *Options is valid and selects the defaults.
+type Options struct {
+	// MinHashSize is the number of values in the MinHash
+	// signature of each page. If it is 0, no signature is
+	// computed.
+	MinHashSize int
+
+	// StoreMainText says whether the text of the main content of
+	// each page is recorded, in addition to its hash.
+	StoreMainText bool
+
+	// RobotsUserAgent determines which robots directives
+	// addressed to specific user-agents apply to the crawler.
+	RobotsUserAgent string
+
+	// SniffContent says whether to infer the media type of a
+	// response from its content, when the declared type is
+	// missing or can't be handled.
+	SniffContent bool
+}
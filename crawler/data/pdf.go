@@ -0,0 +1,111 @@
+package data
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+)
+
+// PDF describes the document information of a PDF. Its title, text
+// and links are recorded in the usual fields of a Result.
+type PDF struct {
+	Author    string
+	Creator   string
+	Producer  string
+	PageCount int
+}
+
+// hydratePDF extracts the metadata, text and links of a PDF into r.
+// PDFs are never HTML documents, so it always returns a nil
+// *html.Node.
+func hydratePDF(r *Result, body io.Reader, header http.Header, opts *Options) (doc *html.Node, err error) {
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+
+	// The PDF reader panics on some malformed documents. The
+	// panic is logged, since errors from content handlers are not
+	// otherwise reported, and the result keeps whatever was read
+	// before it.
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("couldn't read PDF: %v", e)
+			if r.Address != nil {
+				log.Printf("%s: %v", r.Address.Full, err)
+			} else {
+				log.Print(err)
+			}
+		}
+	}()
+
+	rd, err := pdf.NewReader(bytes.NewReader(raw), int64(len(raw)))
+	if err != nil {
+		return nil, err
+	}
+
+	info := rd.Trailer().Key("Info")
+	r.Title = info.Key("Title").Text()
+	r.PDF = &PDF{
+		Author:    info.Key("Author").Text(),
+		Creator:   info.Key("Creator").Text(),
+		Producer:  info.Key("Producer").Text(),
+		PageCount: rd.NumPage(),
+	}
+	r.Links = getPDFLinks(r.Address, rd)
+
+	text, err := getPDFText(rd)
+	if err != nil {
+		return nil, err
+	}
+	r.BodyTextHash = textHash(text)
+	r.WordCount = len(strings.Fields(text))
+	hydrateFingerprints(r, text, opts)
+	r.Language = getLanguage(nil, header, text)
+	return nil, nil
+}
+
+func getPDFText(rd *pdf.Reader) (string, error) {
+	plain, err := rd.GetPlainText()
+	if err != nil {
+		return "", err
+	}
+	text, err := ioutil.ReadAll(plain)
+	if err != nil {
+		return "", err
+	}
+	return string(text), nil
+}
+
+// getPDFLinks returns the targets of the URI link annotations in the
+// document, in page order.
+func getPDFLinks(base *Address, rd *pdf.Reader) (links []*Link) {
+	for i := 1; i <= rd.NumPage(); i++ {
+		annots := rd.Page(i).V.Key("Annots")
+		for j := 0; j < annots.Len(); j++ {
+			a := annots.Index(j)
+			if a.Key("Subtype").Name() != "Link" {
+				continue
+			}
+			action := a.Key("A")
+			if action.Key("S").Name() != "URI" {
+				continue
+			}
+			href := action.Key("URI").RawString()
+			if href == "" {
+				continue
+			}
+			link := MakeLink(base, href, "", "")
+			link.Position = len(links)
+			links = append(links, link)
+		}
+	}
+	return
+}
@@ -0,0 +1,85 @@
+package data
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/benjaminestes/crawl/scrape"
+	"golang.org/x/net/html"
+)
+
+// ClientRedirect is a redirect performed by the page itself rather
+// than by an HTTP status code. Type is "meta" for a meta refresh
+// tag, with Delay in seconds, or "javascript" for an assignment to
+// window.location in an inline script.
+type ClientRedirect struct {
+	Address *Address
+	Href    string
+	Type    string
+	Delay   int
+}
+
+func MakeClientRedirect(base *Address, href, kind string, delay int) *ClientRedirect {
+	redirect := &ClientRedirect{
+		Href:    href,
+		Type:    kind,
+		Delay:   delay,
+		Address: MakeAddressResolved(base, href),
+	}
+	return redirect
+}
+
+// These match the simple forms of client-side redirect in
+// JavaScript: assignment to location or location.href, and calls to
+// location.replace or location.assign, with a string literal.
+var (
+	jsLocationAssign = regexp.MustCompile(`\blocation(?:\.href)?\s*=\s*["']([^"']+)["']`)
+	jsLocationCall   = regexp.MustCompile(`\blocation\.(?:replace|assign)\(\s*["']([^"']+)["']\s*\)`)
+)
+
+func getClientRedirects(base *Address, n *html.Node) (redirects []*ClientRedirect) {
+	for _, meta := range scrape.NodesByTagName("meta", n) {
+		if !strings.EqualFold(scrape.Attribute("http-equiv", meta), "refresh") {
+			continue
+		}
+		delay, href := parseRefresh(scrape.Attribute("content", meta))
+		if href != "" {
+			redirects = append(redirects, MakeClientRedirect(base, href, "meta", delay))
+		}
+	}
+
+	for _, script := range scrape.NodesByTagName("script", n) {
+		if scrape.Attribute("src", script) != "" {
+			continue
+		}
+		text := scrape.Text(script)
+		for _, re := range []*regexp.Regexp{jsLocationAssign, jsLocationCall} {
+			for _, m := range re.FindAllStringSubmatch(text, -1) {
+				redirects = append(redirects, MakeClientRedirect(base, m[1], "javascript", 0))
+			}
+		}
+	}
+
+	return
+}
+
+// parseRefresh interprets the content of a meta refresh tag, as in
+// "5; url=http://www.example.com/". A refresh without a URL reloads
+// the page itself, and produces an empty href.
+func parseRefresh(content string) (delay int, href string) {
+	parts := strings.SplitN(content, ";", 2)
+	if len(parts) == 1 {
+		parts = strings.SplitN(content, ",", 2)
+	}
+	delay, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+	if len(parts) < 2 {
+		return delay, ""
+	}
+	target := strings.TrimSpace(parts[1])
+	if i := strings.Index(target, "="); i >= 0 && strings.EqualFold(strings.TrimSpace(target[:i]), "url") {
+		target = strings.TrimSpace(target[i+1:])
+	}
+	target = strings.Trim(target, `'"`)
+	return delay, target
+}
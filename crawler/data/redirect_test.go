@@ -0,0 +1,30 @@
+package data
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestClientRedirects(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><head>
+<meta http-equiv="Refresh" content="5; URL='/later'">
+<script>if (x == 1) { window.location.href = "/js"; }</script>
+</head></html>`))
+	if err != nil {
+		t.Fatalf("couldn't parse test data")
+	}
+
+	redirects := getClientRedirects(MakeAddress("http://example.com/"), doc)
+
+	if len(redirects) != 2 {
+		t.Fatalf("expected 2 redirects, got %d", len(redirects))
+	}
+	if r := redirects[0]; r.Type != "meta" || r.Delay != 5 || r.Address.Full != "http://example.com/later" {
+		t.Errorf("unexpected redirect %+v", r)
+	}
+	if r := redirects[1]; r.Type != "javascript" || r.Address.Full != "http://example.com/js" {
+		t.Errorf("unexpected redirect %+v", r)
+	}
+}
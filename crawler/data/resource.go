@@ -0,0 +1,34 @@
+package data
+
+// Resource is a subresource referenced by a page: an image, script,
+// stylesheet, frame or media file. Tag and Attribute record where the
+// reference was found, e.g. "img" and "srcset".
+type Resource struct {
+	Address   *Address
+	Href      string
+	Tag       string
+	Attribute string
+	Rel       string
+	Alt       string
+	HasAlt    bool
+	Width     string
+	Height    string
+	Loading   string
+
+	// These fields are only populated if the crawler was
+	// configured to fetch resources.
+	Status        string
+	StatusCode    int
+	ContentType   string
+	ContentLength int64
+}
+
+func MakeResource(base *Address, href, tag, attribute string) *Resource {
+	resource := &Resource{
+		Href:      href,
+		Tag:       tag,
+		Attribute: attribute,
+		Address:   MakeAddressResolved(base, href),
+	}
+	return resource
+}
@@ -1,11 +1,15 @@
 package data
 
 import (
+	"bytes"
 	"crypto/sha512"
 	"encoding/base64"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"strings"
 
+	"github.com/benjaminestes/crawl/fingerprint"
 	"github.com/benjaminestes/crawl/scrape"
 	"golang.org/x/net/html"
 )
@@ -20,17 +24,69 @@ type Result struct {
 	Address *Address `json:",omitempty"`
 	Depth   int      `mode:"REQUIRED"`
 
+	// Discovery says how the crawler first found the URL. See the
+	// Discovery constants.
+	Discovery string
+
+	// SitemapEntry is the sitemap metadata for the URL, if it is
+	// listed in a sitemap that was read for the crawl.
+	SitemapEntry *SitemapEntry `json:",omitempty"`
+
+	// Base is the address given by the page's <base> element, if
+	// any. Relative URLs on the page are resolved against it.
+	Base *Address `json:",omitempty"`
+
 	// Meta
-	BodyTextHash string `json:",omitempty"`
+	MediaType        string
+	MediaTypeSniffed bool
+	Charset          *Charset  `json:",omitempty"`
+	PDF              *PDF      `json:",omitempty"`
+	Language         *Language `json:",omitempty"`
+	BodyTextHash     string    `json:",omitempty"`
+	HTMLSize         int
+	WordCount        int
+	TextRatio        float64 // Visible text bytes per HTML byte
+
+	// MainTextHash and MainWordCount describe only the main
+	// content of the page, excluding navigation and other
+	// boilerplate. MainText is only recorded if requested.
+	MainTextHash  string `json:",omitempty"`
+	MainWordCount int
+	MainText      string `json:",omitempty"`
+
+	// SimHash and MinHash are fingerprints of the visible body
+	// text that are similar for similar pages. See package
+	// fingerprint. SimHash is nil if there is no text, since 0
+	// is a valid SimHash.
+	SimHash *int64  `json:",omitempty"`
+	MinHash []int64 `json:",omitempty"`
+
+	// Indexable says whether a search engine could index the
+	// page. If not, NonIndexableReasons says why.
+	Indexable           bool
+	NonIndexableReasons []string `json:",omitempty"`
 
 	// Content
-	Description string
-	Title       string
-	H1          string
-	Robots      string
-	Canonical   *Canonical  `json:",omitempty"`
-	Links       []*Link     `json:",omitempty"`
-	Hreflang    []*Hreflang `json:",omitempty"`
+	Description   string
+	Title         string
+	H1            string
+	Headings      []*Heading     `json:",omitempty"`
+	HeadingCounts *HeadingCounts `json:",omitempty"`
+	Robots        string
+	Indexability  *Indexability `json:",omitempty"`
+	Canonical     *Canonical    `json:",omitempty"`
+	Canonicals    []*Canonical  `json:",omitempty"`
+	Links         []*Link       `json:",omitempty"`
+	Hreflang      []*Hreflang   `json:",omitempty"`
+	Resources     []*Resource   `json:",omitempty"`
+
+	// CanonicalConflict is true if the page declares more than
+	// one canonical URL, in HTML or Link headers. Canonical is
+	// the first declared in HTML, or else in a header; every
+	// declaration is listed in Canonicals. HreflangConflict is
+	// true if one language code is given more than one URL.
+	CanonicalConflict bool
+	HreflangConflict  bool
 
 	// Response
 	Status     string   `json:",omitempty"`
@@ -40,9 +96,13 @@ type Result struct {
 	ProtoMinor int      `json:",omitempty"`
 	Header     []*Pair  `json:",omitempty"`
 	ResolvesTo *Address `json:",omitempty"` // In case of redirect
+
+	// ClientRedirects are redirects by meta refresh or
+	// JavaScript, which the response status does not reveal.
+	ClientRedirects []*ClientRedirect `json:",omitempty"`
 }
 
-func MakeResult(rawurl string, depth int, resp *http.Response) *Result {
+func MakeResult(rawurl string, depth int, resp *http.Response, opts *Options) *Result {
 	// FIXME: Should this contructor return an error?
 	addr := MakeAddress(rawurl)
 	result := &Result{
@@ -51,21 +111,27 @@ func MakeResult(rawurl string, depth int, resp *http.Response) *Result {
 	}
 
 	if resp != nil {
-		result.hydrate(resp)
+		if opts == nil {
+			opts = &Options{}
+		}
+		result.hydrate(resp, opts)
 	}
+	result.setIndexable()
 	return result
 }
 
-func (r *Result) hydrate(resp *http.Response) {
+func (r *Result) hydrate(resp *http.Response, opts *Options) {
 	hydrateHeader(r, resp)
 
-	if strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html") {
-		doc, err := html.Parse(resp.Body)
-		if err != nil {
-			return
-		}
-		hydrateHTMLContent(r, doc)
-	}
+	// An error here means the content couldn't be read, and
+	// everything else about the response is still worth keeping.
+	doc, _ := hydrateContent(r, resp, opts)
+
+	hydrateLinkHeader(r, resp.Header)
+
+	// Robots directives may come from headers, so they apply to
+	// documents of any type.
+	r.Indexability = getIndexability(doc, resp.Header, opts.RobotsUserAgent)
 
 	// If the result doesn't redirect, we say it resolves to itself.
 	r.ResolvesTo = r.Address
@@ -75,6 +141,28 @@ func (r *Result) hydrate(resp *http.Response) {
 	}
 }
 
+// hydrateHTML parses body as an HTML document and extracts its
+// content into r.
+func hydrateHTML(r *Result, body io.Reader, header http.Header, opts *Options) (*html.Node, error) {
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	decoded, cs := decodeBody(raw, header.Get("Content-Type"))
+	doc, err := html.Parse(bytes.NewReader(decoded))
+	if err != nil {
+		return nil, err
+	}
+	cs.Meta = getMetaCharset(doc)
+	r.Charset = cs
+	hydrateHTMLContent(r, doc)
+	hydrateContentMetrics(r, len(raw), doc)
+	hydrateFingerprints(r, scrape.VisibleText(scrape.Query("body", nil, doc)), opts)
+	hydrateMainContent(r, doc, opts)
+	r.Language = getLanguage(doc, header, scrape.VisibleText(scrape.MainContent(doc)))
+	return doc, nil
+}
+
 func hydrateHeader(r *Result, resp *http.Response) {
 	for k := range resp.Header {
 		r.Header = append(r.Header, &Pair{k, resp.Header.Get(k)})
@@ -106,22 +194,131 @@ func hydrateHTMLContent(r *Result, doc *html.Node) {
 			},
 			doc,
 		))
-	r.Canonical = getCanonical(r.Address, doc)
-	r.Hreflang = getHreflang(r.Address, doc)
-	r.Links = getLinks(r.Address, doc)
 
-	sum := sha512.Sum512([]byte(scrape.Text(scrape.Query("body", nil, doc))))
-	r.BodyTextHash = base64.StdEncoding.EncodeToString(sum[:])
+	// Relative URLs in the document are resolved against its
+	// <base> element, if it has one.
+	base := r.Address
+	r.Base = getBase(r.Address, doc)
+	if r.Base != nil {
+		base = r.Base
+	}
+	r.Canonical = getCanonical(r.Address, base, doc)
+	r.Canonicals = getCanonicals(base, doc)
+	r.Hreflang = getHreflang(base, doc)
+	r.Links = getLinks(base, doc)
+	r.ClientRedirects = getClientRedirects(base, doc)
+	r.Resources = getResources(base, doc)
+
+	r.BodyTextHash = textHash(scrape.Text(scrape.Query("body", nil, doc)))
+}
+
+// textHash returns a digest of text suitable for finding exact
+// duplicates.
+func textHash(text string) string {
+	sum := sha512.Sum512([]byte(text))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// hydrateContentMetrics records the heading outline of doc and
+// measures of how much text it contains. size is the length of the
+// HTML source in bytes.
+func hydrateContentMetrics(r *Result, size int, doc *html.Node) {
+	r.Headings = getHeadings(doc)
+	r.HeadingCounts = &HeadingCounts{}
+	for _, h := range r.Headings {
+		r.HeadingCounts.add(h.Level)
+	}
+
+	text := scrape.VisibleText(scrape.Query("body", nil, doc))
+	r.HTMLSize = size
+	r.WordCount = len(strings.Fields(text))
+	if size > 0 {
+		r.TextRatio = float64(len(text)) / float64(size)
+	}
+}
+
+// hydrateFingerprints records locality-sensitive fingerprints of the
+// text of a document.
+func hydrateFingerprints(r *Result, text string, opts *Options) {
+	if h, ok := fingerprint.SimHash(text); ok {
+		simhash := int64(h)
+		r.SimHash = &simhash
+	}
+	for _, v := range fingerprint.MinHash(text, opts.MinHashSize) {
+		r.MinHash = append(r.MinHash, int64(v))
+	}
+}
+
+// hydrateMainContent records a hash and word count of the main
+// content of doc, as found by scrape.MainContent.
+func hydrateMainContent(r *Result, doc *html.Node, opts *Options) {
+	text := strings.Join(strings.Fields(scrape.VisibleText(scrape.MainContent(doc))), " ")
+	// Pages without main text would all share a hash, and so
+	// appear to duplicate each other.
+	if text != "" {
+		r.MainTextHash = textHash(text)
+	}
+	r.MainWordCount = len(strings.Fields(text))
+	if opts.StoreMainText {
+		r.MainText = text
+	}
+}
+
+func getHeadings(n *html.Node) (headings []*Heading) {
+	els := scrape.NodesByTagNames([]string{"h1", "h2", "h3", "h4", "h5", "h6"}, n)
+	for i, el := range els {
+		headings = append(headings, &Heading{
+			Level: int(el.Data[1] - '0'),
+			Text:  strings.Join(strings.Fields(scrape.Text(el)), " "),
+			Order: i,
+		})
+	}
+	return
 }
 
-func getCanonical(base *Address, n *html.Node) (c *Canonical) {
+// getBase returns the address given by the first <base> element in
+// n with an href attribute, or nil if there is none.
+func getBase(addr *Address, n *html.Node) *Address {
+	for _, el := range scrape.NodesByTagName("base", n) {
+		if scrape.HasAttribute("href", el) {
+			return MakeAddressResolved(addr, scrape.Attribute("href", el))
+		}
+	}
+	return nil
+}
+
+// getCanonical returns the first canonical URL declared in HTML. If
+// there is none, it returns a canonical with an empty href, which
+// refers to the page itself.
+func getCanonical(page, base *Address, n *html.Node) (c *Canonical) {
 	href := scrape.Attribute("href", scrape.Query("link", map[string]string{
 		"rel": "canonical",
 	}, n))
-	return MakeCanonical(base, href)
+	if href == "" {
+		base = page
+	}
+	c = MakeCanonical(base, href)
+	c.Source = "html"
+	return
+}
+
+// getCanonicals returns every canonical URL declared in HTML.
+func getCanonicals(base *Address, n *html.Node) (canonicals []*Canonical) {
+	nodes := scrape.QueryAll("link", map[string]string{
+		"rel": "canonical",
+	}, n)
+
+	for _, n := range nodes {
+		if href := scrape.Attribute("href", n); href != "" {
+			c := MakeCanonical(base, href)
+			c.Source = "html"
+			canonicals = append(canonicals, c)
+		}
+	}
+
+	return
 }
 
-// FIXME: Should get the same URL resolving treatment as links
 func getHreflang(base *Address, n *html.Node) (hreflang []*Hreflang) {
 	nodes := scrape.QueryAll("link", map[string]string{
 		"rel": "alternate",
@@ -131,7 +328,9 @@ func getHreflang(base *Address, n *html.Node) (hreflang []*Hreflang) {
 		lang := scrape.Attribute("hreflang", n)
 		href := scrape.Attribute("href", n)
 		if href != "" {
-			hreflang = append(hreflang, MakeHreflang(base, href, lang))
+			h := MakeHreflang(base, href, lang)
+			h.Source = "html"
+			hreflang = append(hreflang, h)
 		}
 	}
 
@@ -140,15 +339,118 @@ func getHreflang(base *Address, n *html.Node) (hreflang []*Hreflang) {
 
 func getLinks(base *Address, n *html.Node) (links []*Link) {
 	els := scrape.NodesByTagName("a", n)
-	for _, a := range els {
+	for i, a := range els {
 		href := scrape.Attribute("href", a)
 		link := MakeLink(
 			base,
 			href,
 			scrape.Text(a),
-			scrape.Attribute("rel", a) == "nofollow", // FIXME: Trim whitespace?
+			scrape.Attribute("rel", a),
 		)
+		link.Target = scrape.Attribute("target", a)
+		link.Title = scrape.Attribute("title", a)
+		link.Hreflang = scrape.Attribute("hreflang", a)
+		link.Type = scrape.Attribute("type", a)
+		link.Context = linkContext(a)
+		link.Path = scrape.Path(a)
+		link.Position = i
 		links = append(links, link)
 	}
 	return links
 }
+
+// landmarkRoles maps ARIA landmark roles to the equivalent element
+// names, so that either form identifies the context of a link.
+var landmarkRoles = map[string]string{
+	"navigation":    "nav",
+	"banner":        "header",
+	"contentinfo":   "footer",
+	"complementary": "aside",
+	"main":          "main",
+}
+
+// linkContext returns the name of the nearest landmark element
+// enclosing n, or "" if there is none.
+func linkContext(n *html.Node) string {
+	for p := n.Parent; p != nil; p = p.Parent {
+		if p.Type != html.ElementNode {
+			continue
+		}
+		switch p.Data {
+		case "nav", "header", "footer", "aside", "main":
+			return p.Data
+		}
+		if landmark, ok := landmarkRoles[scrape.Attribute("role", p)]; ok {
+			return landmark
+		}
+	}
+	return ""
+}
+
+// resourceAttributes lists, for each tag that can reference a
+// subresource, the attributes that may hold its URL.
+var resourceAttributes = []struct {
+	tag   string
+	attrs []string
+}{
+	{"img", []string{"src", "srcset"}},
+	{"script", []string{"src"}},
+	{"link", []string{"href"}},
+	{"iframe", []string{"src"}},
+	{"video", []string{"src", "poster"}},
+	{"audio", []string{"src"}},
+	{"source", []string{"src", "srcset"}},
+}
+
+func getResources(base *Address, n *html.Node) (resources []*Resource) {
+	for _, ra := range resourceAttributes {
+		for _, el := range scrape.NodesByTagName(ra.tag, n) {
+			rel := scrape.Attribute("rel", el)
+			if ra.tag == "link" && !isResourceRel(rel) {
+				continue
+			}
+			for _, attr := range ra.attrs {
+				var hrefs []string
+				if attr == "srcset" {
+					hrefs = parseSrcset(scrape.Attribute(attr, el))
+				} else if href := scrape.Attribute(attr, el); href != "" {
+					hrefs = []string{href}
+				}
+				for _, href := range hrefs {
+					res := MakeResource(base, href, ra.tag, attr)
+					res.Rel = rel
+					res.Alt = scrape.Attribute("alt", el)
+					res.HasAlt = scrape.HasAttribute("alt", el)
+					res.Width = scrape.Attribute("width", el)
+					res.Height = scrape.Attribute("height", el)
+					res.Loading = scrape.Attribute("loading", el)
+					resources = append(resources, res)
+				}
+			}
+		}
+	}
+	return
+}
+
+// isResourceRel reports whether a <link> element with the given rel
+// attribute refers to a resource the page loads.
+func isResourceRel(rel string) bool {
+	for _, tok := range strings.Fields(strings.ToLower(rel)) {
+		switch tok {
+		case "stylesheet", "preload", "modulepreload":
+			return true
+		}
+	}
+	return false
+}
+
+// parseSrcset returns the URLs of the image candidates in a srcset
+// attribute, dropping their width and density descriptors.
+func parseSrcset(srcset string) (hrefs []string) {
+	for _, candidate := range strings.Split(srcset, ",") {
+		if f := strings.Fields(candidate); len(f) > 0 {
+			hrefs = append(hrefs, f[0])
+		}
+	}
+	return
+}
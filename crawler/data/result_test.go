@@ -0,0 +1,216 @@
+package data
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestBase(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "text/html")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body: ioutil.NopCloser(strings.NewReader(`<html><head>
+<base href="/dir/">
+</head><body><a href="page">Page</a></body></html>`)),
+	}
+
+	r := MakeResult("http://example.com/a/b", 0, resp, nil)
+
+	if r.Base == nil || r.Base.Full != "http://example.com/dir/" {
+		t.Errorf("expected base http://example.com/dir/, got %v", r.Base)
+	}
+	if full := r.Links[0].Address.Full; full != "http://example.com/dir/page" {
+		t.Errorf("expected link to http://example.com/dir/page, got %s", full)
+	}
+	if !r.Indexable {
+		t.Errorf("page without canonical should be indexable, got %v", r.NonIndexableReasons)
+	}
+}
+
+func TestCharset(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "text/html")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body: ioutil.NopCloser(strings.NewReader("<html><head>" +
+			`<meta http-equiv="Content-Type" content="text/html; charset=iso-8859-1">` +
+			"<title>Caf\xe9</title></head></html>")),
+	}
+
+	r := MakeResult("http://example.com/", 0, resp, nil)
+
+	if r.Title != "Café" {
+		t.Errorf(`expected title "Café", got %q`, r.Title)
+	}
+	if r.Charset.Header != "" || r.Charset.Meta != "windows-1252" || r.Charset.Detected != "windows-1252" {
+		t.Errorf("unexpected charset %+v", r.Charset)
+	}
+}
+
+func TestContentMetrics(t *testing.T) {
+	body := "<html><body><h1>Main <em>Title</em></h1><h2>A</h2><p>one two</p>" +
+		"<h2>B</h2><h3>C</h3><script>var x = 1;</script></body></html>"
+	header := http.Header{}
+	header.Set("Content-Type", "text/html")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+
+	r := MakeResult("http://example.com/", 0, resp, nil)
+
+	expected := []Heading{{1, "Main Title", 0}, {2, "A", 1}, {2, "B", 2}, {3, "C", 3}}
+	if len(r.Headings) != len(expected) {
+		t.Fatalf("expected %d headings, got %d", len(expected), len(r.Headings))
+	}
+	for i, h := range r.Headings {
+		if *h != expected[i] {
+			t.Errorf("heading %d: expected %+v, got %+v", i, expected[i], *h)
+		}
+	}
+	if counts := (HeadingCounts{H1: 1, H2: 2, H3: 1}); r.HeadingCounts == nil || *r.HeadingCounts != counts {
+		t.Errorf("expected counts %+v, got %+v", counts, r.HeadingCounts)
+	}
+
+	// The text of the script isn't visible.
+	text := "Main TitleAone twoBC"
+	if r.HTMLSize != len(body) {
+		t.Errorf("expected size %d, got %d", len(body), r.HTMLSize)
+	}
+	if ratio := float64(len(text)) / float64(len(body)); r.TextRatio != ratio {
+		t.Errorf("expected text ratio %v, got %v", ratio, r.TextRatio)
+	}
+}
+
+func TestSniffContent(t *testing.T) {
+	for _, tt := range []struct {
+		contentType string
+		sniff       bool
+		body        string
+		mediaType   string
+		links       int
+	}{
+		{"application/xhtml+xml", false, `<html xmlns="http://www.w3.org/1999/xhtml"><body><a href="/a">A</a></body></html>`, "application/xhtml+xml", 1},
+		{"", false, `<html><body><a href="/a">A</a></body></html>`, "", 0},
+		{"", true, `<html><body><a href="/a">A</a></body></html>`, "text/html", 1},
+		{"text/plain", true, `<?xml version="1.0"?><html xmlns="http://www.w3.org/1999/xhtml"><body><a href="/a">A</a></body></html>`, "application/xhtml+xml", 1},
+	} {
+		header := http.Header{}
+		if tt.contentType != "" {
+			header.Set("Content-Type", tt.contentType)
+		}
+		resp := &http.Response{
+			StatusCode: 200,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(tt.body)),
+		}
+
+		r := MakeResult("http://example.com/", 0, resp, &Options{SniffContent: tt.sniff})
+
+		if r.MediaType != tt.mediaType || len(r.Links) != tt.links {
+			t.Errorf("%q, sniff=%v: expected %q with %d links, got %q with %d links",
+				tt.contentType, tt.sniff, tt.mediaType, tt.links, r.MediaType, len(r.Links))
+		}
+	}
+}
+
+func TestPDF(t *testing.T) {
+	f, err := os.Open("testdata/simple.pdf")
+	if err != nil {
+		t.Fatalf("couldn't open test data")
+	}
+	defer f.Close()
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/pdf")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body:       f,
+	}
+
+	r := MakeResult("http://example.com/simple.pdf", 0, resp, nil)
+
+	if r.Title != "A simple PDF" || r.PDF == nil || r.PDF.Author != "Jane Doe" || r.PDF.PageCount != 1 {
+		t.Errorf("unexpected PDF metadata %q %+v", r.Title, r.PDF)
+	}
+	if len(r.Links) != 1 || r.Links[0].Address.Full != "http://www.example.com/linked" {
+		t.Errorf("expected link to http://www.example.com/linked, got %+v", r.Links)
+	}
+	if r.BodyTextHash == "" || r.WordCount == 0 {
+		t.Errorf("expected PDF text to be hashed and counted")
+	}
+}
+
+func TestLanguage(t *testing.T) {
+	for _, tt := range []struct {
+		contentLanguage string
+		html            string
+		detected        string
+		mismatch        bool
+	}{
+		// Declared German, written in English.
+		{"de-DE, en", `<html lang="de"><head>
+<meta property="og:locale" content="de_DE">
+</head><body><p>The quick brown fox jumps over the lazy dog, and then it runs back into the forest where it lives.</p></body></html>`, "en", true},
+		// Norwegian can't be detected, and is detected as Danish.
+		{"nb", `<html lang="nb"><head>
+<meta property="og:locale" content="nb_NO">
+</head><body><p>Den raske brune reven hopper over den late hunden og løper så tilbake til skogen der den bor med familien sin.</p></body></html>`, "da", false},
+		// Navigation text is too little like any language.
+		{"en", `<html lang="en"><body><nav>Home About Contact Login Register Search Menu Blog News Shop Cart Account Privacy Terms Sitemap</nav></body></html>`, "", false},
+	} {
+		header := http.Header{}
+		header.Set("Content-Type", "text/html")
+		header.Set("Content-Language", tt.contentLanguage)
+		resp := &http.Response{
+			StatusCode: 200,
+			Header:     header,
+			Body:       ioutil.NopCloser(strings.NewReader(tt.html)),
+		}
+
+		r := MakeResult("http://example.com/", 0, resp, nil)
+
+		if l := r.Language; l.Detected != tt.detected || l.Mismatch != tt.mismatch {
+			t.Errorf("expected detected %q and mismatch %v, got %+v", tt.detected, tt.mismatch, l)
+		}
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "text/html")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body: ioutil.NopCloser(strings.NewReader(`<html lang="de"><head>
+<meta property="og:locale" content="de_DE">
+</head></html>`)),
+	}
+	if l := MakeResult("http://example.com/", 0, resp, nil).Language; l.HTMLLang != "de" || l.OGLocale != "de_DE" {
+		t.Errorf("unexpected language signals %+v", l)
+	}
+}
+
+func TestLanguageUndetected(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Type", "text/html")
+	resp := &http.Response{
+		StatusCode: 200,
+		Header:     header,
+		Body: ioutil.NopCloser(strings.NewReader(`<html lang="ja"><body>
+<p>東京は日本の首都であり、世界でも有数の大都市です。多くの人々が毎日ここで働いています。</p>
+</body></html>`)),
+	}
+
+	r := MakeResult("http://example.com/", 0, resp, nil)
+
+	if l := r.Language; l.Detected != "" || l.Mismatch {
+		t.Errorf("expected undetected language without mismatch, got %+v", l)
+	}
+}
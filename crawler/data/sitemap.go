@@ -0,0 +1,65 @@
+package data
+
+// SitemapEntry is the metadata a sitemap gave for a URL. Sitemap is
+// the location of that sitemap, if known. Hreflang lists the
+// alternates declared with xhtml:link elements, with Source
+// "sitemap".
+type SitemapEntry struct {
+	Sitemap    string
+	Lastmod    string
+	Changefreq string
+	Priority   string
+	Images     []*SitemapImage
+	Videos     []*SitemapVideo
+	News       *SitemapNews
+	Hreflang   []*Hreflang
+}
+
+// SitemapImage is an image listed for a URL with the sitemap image
+// extension.
+type SitemapImage struct {
+	Loc         string
+	Caption     string
+	Title       string
+	GeoLocation string
+	License     string
+}
+
+// SitemapVideo is a video listed for a URL with the sitemap video
+// extension.
+type SitemapVideo struct {
+	ThumbnailLoc    string
+	Title           string
+	Description     string
+	ContentLoc      string
+	PlayerLoc       string
+	Duration        string
+	ExpirationDate  string
+	PublicationDate string
+	FamilyFriendly  string
+	Tags            []string
+}
+
+// SitemapNews is the article described for a URL with the sitemap
+// news extension.
+type SitemapNews struct {
+	PublicationName     string
+	PublicationLanguage string
+	PublicationDate     string
+	Title               string
+	Keywords            string
+}
+
+// MakeSitemapHreflang creates an alternate declared by a sitemap for
+// the URL loc.
+func MakeSitemapHreflang(loc, href, lang string) *Hreflang {
+	h := &Hreflang{
+		Href:     href,
+		Hreflang: lang,
+		Source:   "sitemap",
+	}
+	if base := MakeAddress(loc); base != nil {
+		h.Address = MakeAddressResolved(base, href)
+	}
+	return h
+}
@@ -0,0 +1,112 @@
+// Copyright 2018 Benjamin Estes. All rights reserved.  Use of this
+// source code is governed by an MIT-style license that can be found
+// in the LICENSE file.
+
+package crawler
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+)
+
+// resourceFetch is the response to a resource, which is complete
+// once done is closed.
+type resourceFetch struct {
+	done chan struct{}
+	res  *data.Resource
+}
+
+// fetchResources requests each resource using the method configured
+// in c.FetchResources, and records the response on the resource.
+// Each distinct URL is requested at most once per crawl: a page
+// needing a resource that another page is already requesting waits
+// for that response.
+//
+// Like pages, resources are only requested if they are in the scope
+// of the crawl and allowed by robots.txt, and no sooner than
+// c.WaitTime after the last request.
+func (c *Crawler) fetchResources(resources []*data.Resource) {
+	for _, res := range resources {
+		// Only resources on the web can be requested, not
+		// data: URIs and the like.
+		if res.Address == nil || (res.Address.Scheme != "http" && res.Address.Scheme != "https") {
+			continue
+		}
+		if !c.willCrawl(resolvedURL(res.Address.Full)) {
+			continue
+		}
+
+		c.rmu.Lock()
+		f, ok := c.resources[res.Address.Full]
+		if !ok {
+			f = &resourceFetch{done: make(chan struct{})}
+			c.resources[res.Address.Full] = f
+		}
+		c.rmu.Unlock()
+
+		if ok {
+			<-f.done
+		} else {
+			f.res = c.fetchResource(res.Address.Full)
+			close(f.done)
+		}
+		res.Status = f.res.Status
+		res.StatusCode = f.res.StatusCode
+		res.ContentType = f.res.ContentType
+		res.ContentLength = f.res.ContentLength
+	}
+}
+
+// fetchResource requests a single resource. Only the parts of the
+// response that describe it are kept.
+func (c *Crawler) fetchResource(fullurl string) *data.Resource {
+	res := &data.Resource{}
+
+	allowed, err := c.robotsAllow(fullurl)
+	if err != nil {
+		res.Status = err.Error()
+		return res
+	}
+	if !allowed {
+		res.Status = "Blocked by robots.txt"
+		return res
+	}
+
+	req, err := http.NewRequest(c.FetchResources, fullurl, nil)
+	if err != nil {
+		res.Status = err.Error()
+		return res
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+	for _, h := range c.Header {
+		req.Header.Add(h.K, h.V)
+	}
+
+	c.awaitTurn()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		res.Status = err.Error()
+		return res
+	}
+	defer resp.Body.Close()
+
+	res.Status = resp.Status
+	res.StatusCode = resp.StatusCode
+	res.ContentType = resp.Header.Get("Content-Type")
+	res.ContentLength = resp.ContentLength
+	if req.Method == "GET" {
+		// The declared length may be missing or wrong, so
+		// count what was actually sent.
+		n, err := io.Copy(ioutil.Discard, resp.Body)
+		if err == nil {
+			res.ContentLength = n
+		}
+	}
+	if res.ContentLength < 0 {
+		res.ContentLength = 0
+	}
+	return res
+}
@@ -5,6 +5,7 @@ import (
 	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 )
 
@@ -112,3 +113,387 @@ func TestAllowServer(t *testing.T) {
 		t.Errorf("expected %d URLs, returned %d", wantCount, count)
 	}
 }
+
+func TestFetchResources(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\nallow: /\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/" {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<img src="/ok.png" alt=""><img src="/missing.png">`)
+	})
+	mux.HandleFunc("/ok.png", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		fmt.Fprintf(w, "png")
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := &Crawler{
+		From:            []string{ts.URL},
+		RobotsUserAgent: "Crawler",
+		WaitTime:        "1ms",
+		FetchResources:  "GET",
+	}
+
+	err := c.Start()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	n := c.Next()
+	if len(n.Resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(n.Resources))
+	}
+	if ok := n.Resources[0]; ok.StatusCode != 200 || ok.ContentLength != 3 || !ok.HasAlt {
+		t.Errorf("unexpected resource %+v", ok)
+	}
+	if missing := n.Resources[1]; missing.StatusCode != 404 || missing.HasAlt {
+		t.Errorf("unexpected resource %+v", missing)
+	}
+}
+
+func TestFetchResourcesOnce(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\ndisallow: /private/\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		requests[req.URL.Path]++
+		mu.Unlock()
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<img src="/shared.png"><img src="/private/x.png"><img src="data:image/png;base64,AA==">`)
+		for i := 0; i < 5 && req.URL.Path == "/"; i++ {
+			fmt.Fprintf(w, `<a href="/page%d">Page</a>`, i)
+		}
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	c := &Crawler{
+		From:            []string{ts.URL},
+		RobotsUserAgent: "Crawler",
+		Connections:     5,
+		MaxDepth:        1,
+		WaitTime:        "1ms",
+		FetchResources:  "HEAD",
+	}
+
+	err := c.Start()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	for n := c.Next(); n != nil; n = c.Next() {
+		if len(n.Resources) != 3 {
+			t.Fatalf("expected 3 resources, got %d", len(n.Resources))
+		}
+		if blocked := n.Resources[1]; blocked.Status != "Blocked by robots.txt" {
+			t.Errorf("expected resource to be blocked, got %+v", blocked)
+		}
+		if inline := n.Resources[2]; inline.Status != "" {
+			t.Errorf("expected data URI not to be requested, got %+v", inline)
+		}
+	}
+
+	if requests["/shared.png"] != 1 {
+		t.Errorf("expected shared resource to be requested once, got %d", requests["/shared.png"])
+	}
+	if requests["/private/x.png"] != 0 {
+		t.Errorf("expected blocked resource not to be requested")
+	}
+}
+
+func TestRobotsAllowConcurrent(t *testing.T) {
+	release := make(chan struct{})
+	var mu sync.Mutex
+	var slowRequests int
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		mu.Lock()
+		slowRequests++
+		mu.Unlock()
+		<-release
+		fmt.Fprintf(w, "user-agent: *\ndisallow: /\n")
+	}))
+	defer slow.Close()
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\nallow: /\n")
+	}))
+	defer fast.Close()
+
+	c := &Crawler{From: []string{fast.URL}, RobotsUserAgent: "Crawler"}
+	if err := c.Start(); err != nil {
+		t.Fatalf("%v", err)
+	}
+	for c.Next() != nil {
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if allowed, _ := c.robotsAllow(slow.URL + "/x"); allowed {
+				t.Errorf("expected slow site to disallow")
+			}
+		}()
+	}
+
+	// The slow site's robots.txt must not hold up other sites.
+	if allowed, _ := c.robotsAllow(fast.URL + "/x"); !allowed {
+		t.Errorf("expected fast site to allow")
+	}
+	close(release)
+	wg.Wait()
+
+	if slowRequests != 1 {
+		t.Errorf("expected robots.txt to be requested once, got %d", slowRequests)
+	}
+}
+
+func TestFetchResourcesMethod(t *testing.T) {
+	c := &Crawler{
+		From:           []string{"http://localhost/"},
+		FetchResources: "POST",
+	}
+	if err := c.Start(); err == nil {
+		t.Errorf("expected error for method POST")
+	}
+}
+
+func TestRespectPageNofollow(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\nallow: /\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if req.URL.Path == "/" {
+			w.Header().Set("X-Robots-Tag", "nofollow")
+		}
+		fmt.Fprintf(w, `<a href="/child">Child</a>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, respect := range []bool{true, false} {
+		c := &Crawler{
+			From:                []string{ts.URL},
+			MaxDepth:            1,
+			RobotsUserAgent:     "Crawler",
+			WaitTime:            "1ms",
+			RespectPageNofollow: respect,
+		}
+
+		err := c.Start()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		var count int
+		for n := c.Next(); n != nil; n = c.Next() {
+			count++
+		}
+
+		wantCount := 2
+		if respect {
+			wantCount = 1
+		}
+		if count != wantCount {
+			t.Errorf("RespectPageNofollow=%v: expected %d URLs, returned %d", respect, wantCount, count)
+		}
+	}
+}
+
+func TestSkipNoindexLinks(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\nallow: /\n")
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		if req.URL.Path == "/" {
+			fmt.Fprintf(w, `<meta name="robots" content="noindex">`)
+		}
+		fmt.Fprintf(w, `<a href="/child">Child</a>`)
+	})
+
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	for _, skip := range []bool{true, false} {
+		c := &Crawler{
+			From:             []string{ts.URL},
+			MaxDepth:         1,
+			RobotsUserAgent:  "Crawler",
+			WaitTime:         "1ms",
+			SkipNoindexLinks: skip,
+		}
+
+		err := c.Start()
+		if err != nil {
+			t.Fatalf("%v", err)
+		}
+
+		var count int
+		for n := c.Next(); n != nil; n = c.Next() {
+			count++
+		}
+
+		wantCount := 2
+		if skip {
+			wantCount = 1
+		}
+		if count != wantCount {
+			t.Errorf("SkipNoindexLinks=%v: expected %d URLs, returned %d", skip, wantCount, count)
+		}
+	}
+}
+
+func TestCrawlSitemaps(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\nallow: /\nsitemap: %s/map.xml\n", ts.URL)
+	})
+	mux.HandleFunc("/map.xml", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%[1]s/orphan</loc></url><url><loc>%[1]s/child</loc></url></urlset>`, ts.URL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<a href="/child">Child</a><a href="/other">Other</a>`)
+	})
+
+	c := &Crawler{
+		From:            []string{ts.URL},
+		MaxDepth:        1,
+		RobotsUserAgent: "Crawler",
+		WaitTime:        "1ms",
+		CrawlSitemaps:   true,
+	}
+
+	err := c.Start()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	discovery := make(map[string]string)
+	for n := c.Next(); n != nil; n = c.Next() {
+		discovery[n.Address.Path] = n.Discovery
+		if n.Address.Path == "/orphan" && (n.SitemapEntry == nil || n.SitemapEntry.Sitemap != ts.URL+"/map.xml") {
+			t.Errorf("expected sitemap entry for /orphan, got %+v", n.SitemapEntry)
+		}
+	}
+
+	want := map[string]string{
+		"/":       "start",
+		"/orphan": "sitemap",
+		"/child":  "sitemap",
+		"/other":  "link",
+	}
+	if fmt.Sprint(discovery) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, discovery)
+	}
+}
+
+func TestCrawlSitemapsRequests(t *testing.T) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	count := func(req *http.Request) {
+		mu.Lock()
+		requests[req.URL.Path]++
+		mu.Unlock()
+	}
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		count(req)
+		fmt.Fprintf(w, "user-agent: *\nallow: /\n")
+	})
+	mux.HandleFunc("/sitemap.xml", func(w http.ResponseWriter, req *http.Request) {
+		count(req)
+		fmt.Fprintf(w, `<urlset><url><loc>%s/a</loc></url></urlset>`, ts.URL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+
+	c := &Crawler{
+		From:            []string{ts.URL},
+		RobotsUserAgent: "Crawler",
+		WaitTime:        "1ms",
+		CrawlSitemaps:   true,
+	}
+
+	err := c.Start()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var paths []string
+	for n := c.Next(); n != nil; n = c.Next() {
+		paths = append(paths, n.Address.Path)
+	}
+	if fmt.Sprint(paths) != "[/ /a]" {
+		t.Errorf("expected [/ /a], got %v", paths)
+	}
+	// robots.txt is read once for sitemaps and rules, and the
+	// sitemap once to find and to read it.
+	if requests["/robots.txt"] != 1 || requests["/sitemap.xml"] != 1 {
+		t.Errorf("expected robots.txt and sitemap to be requested once, got %v", requests)
+	}
+}
+
+func TestCrawlSitemapsLimit(t *testing.T) {
+	mux := http.NewServeMux()
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, "user-agent: *\nallow: /\nsitemap: %s/map.xml\n", ts.URL)
+	})
+	mux.HandleFunc("/map.xml", func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprintf(w, `<urlset><url><loc>%[1]s/a</loc></url><url><loc>%[1]s/b</loc></url></urlset>`, ts.URL)
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+	})
+
+	c := &Crawler{
+		From:            []string{ts.URL},
+		RobotsUserAgent: "Crawler",
+		WaitTime:        "1ms",
+		CrawlSitemaps:   true,
+		SitemapMaxURLs:  1,
+	}
+
+	err := c.Start()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var paths []string
+	for n := c.Next(); n != nil; n = c.Next() {
+		paths = append(paths, n.Address.Path)
+	}
+	if fmt.Sprint(paths) != "[/ /a]" {
+		t.Errorf("expected [/ /a], got %v", paths)
+	}
+}
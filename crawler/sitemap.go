@@ -0,0 +1,146 @@
+package crawler
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+	"github.com/benjaminestes/crawl/sitemap"
+	"github.com/benjaminestes/robots"
+)
+
+// SitemapFetcher returns a sitemap fetcher that makes requests with
+// the UserAgent, Header and Connections of c, within its
+// SitemapMaxDepth and SitemapMaxURLs. Like those of c, the limits of
+// the fetcher are 0 when there are none.
+func (c *Crawler) SitemapFetcher() *sitemap.Fetcher {
+	header := make(http.Header)
+	for _, h := range c.Header {
+		header.Add(h.K, h.V)
+	}
+	f := &sitemap.Fetcher{
+		UserAgent:   c.UserAgent,
+		Header:      header,
+		Connections: c.Connections,
+		MaxDepth:    c.SitemapMaxDepth,
+		MaxURLs:     c.SitemapMaxURLs,
+	}
+	switch {
+	case f.MaxDepth == 0:
+		f.MaxDepth = sitemap.DefaultMaxDepth
+	case f.MaxDepth < 0:
+		f.MaxDepth = 0
+	}
+	switch {
+	case f.MaxURLs == 0:
+		f.MaxURLs = sitemap.DefaultMaxURLs
+	case f.MaxURLs < 0:
+		f.MaxURLs = 0
+	}
+	return f
+}
+
+// AddSitemapEntries adds the URLs of entries to From, and records
+// their sitemap metadata in SitemapEntries. A URL already in
+// SitemapEntries is not added again.
+func (c *Crawler) AddSitemapEntries(entries []*sitemap.Entry) {
+	if c.SitemapEntries == nil {
+		c.SitemapEntries = make(map[string]*data.SitemapEntry)
+	}
+	for _, e := range entries {
+		if _, ok := c.SitemapEntries[e.Loc]; ok {
+			continue
+		}
+		c.From = append(c.From, e.Loc)
+		c.SitemapEntries[e.Loc] = sitemapEntry(e)
+	}
+}
+
+// sitemapEntry converts a sitemap entry to the form recorded with
+// the result for its URL.
+func sitemapEntry(e *sitemap.Entry) *data.SitemapEntry {
+	se := &data.SitemapEntry{
+		Sitemap:    e.Sitemap,
+		Lastmod:    e.Lastmod,
+		Changefreq: e.Changefreq,
+		Priority:   e.Priority,
+	}
+	for _, img := range e.Images {
+		i := data.SitemapImage(*img)
+		se.Images = append(se.Images, &i)
+	}
+	for _, vid := range e.Videos {
+		v := data.SitemapVideo(*vid)
+		se.Videos = append(se.Videos, &v)
+	}
+	if e.News != nil {
+		n := data.SitemapNews(*e.News)
+		se.News = &n
+	}
+	for _, a := range e.Alternates {
+		if a.Rel != "alternate" || a.Hreflang == "" {
+			continue
+		}
+		se.Hreflang = append(se.Hreflang, data.MakeSitemapHreflang(e.Loc, a.Href, a.Hreflang))
+	}
+	return se
+}
+
+// seedSitemaps adds the URLs listed in the sitemaps of each site in
+// the initial queue to that queue, if they are in the scope of the
+// crawl. A site's sitemaps are those listed in its robots.txt file,
+// which is kept for the crawl, followed by those found at
+// sitemap.WellKnownPaths. No more than the fetcher's MaxURLs are read
+// in all.
+func (c *Crawler) seedSitemaps() {
+	f := c.SitemapFetcher()
+	limit := f.MaxURLs
+	sites := make(map[string]bool)
+	for _, start := range c.queue {
+		rtxtURL, err := robots.Locate(start.String())
+		if err != nil || sites[rtxtURL] {
+			continue
+		}
+		sites[rtxtURL] = true
+
+		candidates, err := sitemap.WellKnown(rtxtURL)
+		if err != nil {
+			log.Printf("couldn't discover sitemaps of %s: %v", start, err)
+			continue
+		}
+		sitemaps := f.Probe(c.siteRobots(rtxtURL).sitemaps, candidates)
+		for _, s := range sitemaps {
+			if limit > 0 && f.MaxURLs <= 0 {
+				log.Printf("sitemap URL limit of %d reached", limit)
+				return
+			}
+			entries, errs := f.FetchDiscovered(s)
+			for _, err := range errs {
+				log.Printf("%v", err)
+			}
+			if limit > 0 {
+				f.MaxURLs -= len(entries)
+			}
+			c.seedEntries(entries)
+		}
+	}
+}
+
+// seedEntries adds the URLs of entries to the queue.
+func (c *Crawler) seedEntries(entries []*sitemap.Entry) {
+	for _, e := range entries {
+		addr, err := resolve(e.Loc)
+		if err != nil {
+			continue
+		}
+		if _, ok := c.sitemapEntries[addr]; !ok {
+			c.sitemapEntries[addr] = sitemapEntry(e)
+		}
+		if c.seen[addr] || !c.willCrawl(addr) {
+			continue
+		}
+		c.seen[addr] = true
+		c.discovery[addr] = data.DiscoverySitemap
+		c.queue = append(c.queue, addr)
+	}
+}
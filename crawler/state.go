@@ -8,14 +8,23 @@ import (
 	"time"
 
 	"github.com/benjaminestes/crawl/crawler/data"
-	"github.com/benjaminestes/robots"
 )
 
 // A crawlfn represents a state of the crawler state machine.  Its
 // return value is the next state.
 type crawlfn func(*Crawler) crawlfn
 
-// crawlStartQueue is the initial state. If the current queue is
+// crawlSeedSitemaps is the initial state. It adds the URLs in the
+// sitemaps of the sites being crawled to the initial queue, if
+// requested.
+func crawlSeedSitemaps(c *Crawler) crawlfn {
+	if c.CrawlSitemaps {
+		c.seedSitemaps()
+	}
+	return crawlStartQueue
+}
+
+// crawlStartQueue begins each level of the crawl. If the current queue is
 // empty, it returns nil. This is the ultimate termination condition.
 func crawlStartQueue(c *Crawler) crawlfn {
 	if len(c.queue) > 0 {
@@ -27,7 +36,7 @@ func crawlStartQueue(c *Crawler) crawlfn {
 // crawlStart is the beginning of the process of crawling a single
 // URL.
 func crawlStart(c *Crawler) crawlfn {
-	if time.Since(c.lastRequestTime) < c.wait {
+	if c.sinceLastRequest() < c.wait {
 		return crawlWait
 	}
 	return crawlCheckRobots
@@ -36,7 +45,7 @@ func crawlStart(c *Crawler) crawlfn {
 // crawlWait pauses if c.WaitTime has not elapsed since spawning the
 // last request.
 func crawlWait(c *Crawler) crawlfn {
-	time.Sleep(c.wait - time.Since(c.lastRequestTime))
+	time.Sleep(c.wait - c.sinceLastRequest())
 	return crawlStart
 }
 
@@ -45,18 +54,14 @@ func crawlWait(c *Crawler) crawlfn {
 // the URL is in the scope of the crawl as defined by the end user.
 func crawlCheckRobots(c *Crawler) crawlfn {
 	addr := c.queue[0]
-	rtxtURL, err := robots.Locate(addr.String())
+	allowed, err := c.robotsAllow(addr.String())
 	if err != nil {
 		// Couldn't parse URL. Is this the desired behavior?
 		return crawlNext
 	}
-	if _, ok := c.robots[rtxtURL]; !ok {
-		c.addRobots(rtxtURL)
-	}
-	if !c.robots[rtxtURL](addr.String()) {
-		// FIXME: Can this be some sort of "emit error" func?
-		result := data.MakeResult(addr.String(), c.depth, nil)
-		result.Status = "Blocked by robots.txt"
+	if !allowed {
+		result := data.MakeBlockedResult(addr.String(), c.depth, c.options)
+		c.annotate(result, addr)
 		c.results <- result
 		return crawlNext
 	}
@@ -0,0 +1,174 @@
+package fingerprint
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// ClusterSimHash groups the indices of hashes whose SimHash
+// similarity is at least threshold, transitively. Only groups with
+// more than one member are returned. threshold must be greater
+// than 0.
+//
+// Rather than comparing every pair, the bits of each hash are split
+// into more bands than the number of bits two similar hashes may
+// differ by. Similar hashes must then agree on at least one band, so
+// only hashes that share a band are compared.
+func ClusterSimHash(hashes []uint64, threshold float64) [][]int {
+	// Hashes at distance 64 have similarity 0, so at most 63 bits
+	// may differ. At least 2 bands are used so that no band is
+	// the whole hash; extra bands only find extra candidates.
+	maxDist := int(math.Floor((1 - threshold) * 64))
+	if maxDist < 1 {
+		maxDist = 1
+	}
+	if maxDist > 63 {
+		maxDist = 63
+	}
+	bands := maxDist + 1
+	width := 64 / bands
+
+	uf := newUnionFind(len(hashes))
+	for b := 0; b < bands; b++ {
+		lo := uint(b * width)
+		hi := lo + uint(width)
+		if b == bands-1 {
+			hi = 64
+		}
+		buckets := make(map[uint64][]int)
+		for i, h := range hashes {
+			key := (h >> lo) & (1<<(hi-lo) - 1)
+			buckets[key] = append(buckets[key], i)
+		}
+		for _, bucket := range buckets {
+			uf.unionSimilar(bucket, func(i, j int) bool {
+				return SimHashSimilarity(hashes[i], hashes[j]) >= threshold
+			})
+		}
+	}
+	return uf.groups()
+}
+
+// ClusterMinHash groups the indices of sigs whose MinHash similarity
+// is at least threshold, transitively. Only groups with more than
+// one member are returned.
+//
+// Candidate pairs are found by locality-sensitive hashing: each
+// signature is split into bands, and signatures that agree on a
+// whole band are compared. The band size is chosen so that pairs
+// near the threshold are likely to be found, but unlike
+// ClusterSimHash, some similar pairs may be missed.
+func ClusterMinHash(sigs [][]uint64, threshold float64) [][]int {
+	size := 0
+	for _, sig := range sigs {
+		if len(sig) > size {
+			size = len(sig)
+		}
+	}
+	rows := bandRows(size, threshold)
+
+	uf := newUnionFind(len(sigs))
+	for lo := 0; lo < size; lo += rows {
+		hi := lo + rows
+		if hi > size {
+			hi = size
+		}
+		buckets := make(map[string][]int)
+		for i, sig := range sigs {
+			if len(sig) != size {
+				continue
+			}
+			key := make([]byte, 8*(hi-lo))
+			for j, v := range sig[lo:hi] {
+				binary.LittleEndian.PutUint64(key[8*j:], v)
+			}
+			buckets[string(key)] = append(buckets[string(key)], i)
+		}
+		for _, bucket := range buckets {
+			uf.unionSimilar(bucket, func(i, j int) bool {
+				return MinHashSimilarity(sigs[i], sigs[j]) >= threshold
+			})
+		}
+	}
+	return uf.groups()
+}
+
+// bandRows chooses the number of rows per band for a signature of
+// the given size, such that the similarity at which a pair has an
+// even chance of becoming a candidate lies just below threshold.
+func bandRows(size int, threshold float64) int {
+	best, bestDiff := 1, math.Inf(1)
+	for rows := 1; rows <= size; rows++ {
+		bands := float64(size / rows)
+		t := math.Pow(1/bands, 1/float64(rows))
+		if t > threshold {
+			break
+		}
+		if diff := threshold - t; diff < bestDiff {
+			best, bestDiff = rows, diff
+		}
+	}
+	return best
+}
+
+// unionFind is a disjoint-set forest over the integers [0, n).
+type unionFind struct {
+	parent []int
+}
+
+func newUnionFind(n int) *unionFind {
+	uf := &unionFind{parent: make([]int, n)}
+	for i := range uf.parent {
+		uf.parent[i] = i
+	}
+	return uf
+}
+
+func (uf *unionFind) find(i int) int {
+	for uf.parent[i] != i {
+		uf.parent[i] = uf.parent[uf.parent[i]]
+		i = uf.parent[i]
+	}
+	return i
+}
+
+func (uf *unionFind) union(i, j int) {
+	uf.parent[uf.find(i)] = uf.find(j)
+}
+
+// unionSimilar joins every pair of members of bucket that are not
+// already in the same set and for which similar returns true.
+func (uf *unionFind) unionSimilar(bucket []int, similar func(i, j int) bool) {
+	for x := 0; x < len(bucket); x++ {
+		for y := x + 1; y < len(bucket); y++ {
+			i, j := bucket[x], bucket[y]
+			if uf.find(i) != uf.find(j) && similar(i, j) {
+				uf.union(i, j)
+			}
+		}
+	}
+}
+
+// groups returns the sets with more than one member, each in
+// ascending order, ordered by their smallest member.
+func (uf *unionFind) groups() [][]int {
+	index := make(map[int]int)
+	var groups [][]int
+	for i := range uf.parent {
+		root := uf.find(i)
+		g, ok := index[root]
+		if !ok {
+			g = len(groups)
+			index[root] = g
+			groups = append(groups, nil)
+		}
+		groups[g] = append(groups[g], i)
+	}
+	var result [][]int
+	for _, g := range groups {
+		if len(g) > 1 {
+			result = append(result, g)
+		}
+	}
+	return result
+}
@@ -0,0 +1,123 @@
+// Package fingerprint is an internal package of the tool Crawl,
+// responsible for computing locality-sensitive fingerprints of page
+// text, so that pages with nearly identical content can be grouped.
+//
+// Two kinds of fingerprint are provided. A SimHash is a single
+// 64-bit value; similar texts have SimHashes that differ in few
+// bits. A MinHash signature is a list of values; the fraction of
+// positions at which two signatures agree estimates the Jaccard
+// similarity of the texts. Both are computed over overlapping word
+// shingles.
+package fingerprint
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"strings"
+)
+
+// ShingleSize is the number of consecutive words in each shingle.
+const ShingleSize = 3
+
+// shingles returns the hashes of the overlapping word sequences of
+// length ShingleSize in text. Case and whitespace are ignored. Texts
+// shorter than ShingleSize words produce a single shingle; empty
+// texts produce none.
+func shingles(text string) []uint64 {
+	words := strings.Fields(strings.ToLower(text))
+	if len(words) == 0 {
+		return nil
+	}
+	n := len(words) - ShingleSize + 1
+	if n < 1 {
+		n = 1
+	}
+	hashes := make([]uint64, 0, n)
+	for i := 0; i < n; i++ {
+		end := i + ShingleSize
+		if end > len(words) {
+			end = len(words)
+		}
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(words[i:end], " ")))
+		hashes = append(hashes, h.Sum64())
+	}
+	return hashes
+}
+
+// SimHash returns the SimHash of text, and false if text contains no
+// words.
+func SimHash(text string) (uint64, bool) {
+	sh := shingles(text)
+	if len(sh) == 0 {
+		return 0, false
+	}
+	var v [64]int
+	for _, h := range sh {
+		for i := uint(0); i < 64; i++ {
+			if h&(1<<i) != 0 {
+				v[i]++
+			} else {
+				v[i]--
+			}
+		}
+	}
+	var hash uint64
+	for i := uint(0); i < 64; i++ {
+		if v[i] > 0 {
+			hash |= 1 << i
+		}
+	}
+	return hash, true
+}
+
+// SimHashSimilarity returns the fraction of bits that are equal in a
+// and b.
+func SimHashSimilarity(a, b uint64) float64 {
+	return 1 - float64(bits.OnesCount64(a^b))/64
+}
+
+// MinHash returns a MinHash signature of text with size values, or
+// nil if text contains no words.
+func MinHash(text string, size int) []uint64 {
+	sh := shingles(text)
+	if len(sh) == 0 || size < 1 {
+		return nil
+	}
+	sig := make([]uint64, size)
+	for i := range sig {
+		seed := mix(uint64(i + 1))
+		min := ^uint64(0)
+		for _, h := range sh {
+			if v := mix(h ^ seed); v < min {
+				min = v
+			}
+		}
+		sig[i] = min
+	}
+	return sig
+}
+
+// MinHashSimilarity returns the fraction of positions at which a and
+// b agree. Signatures of different sizes have no similarity.
+func MinHashSimilarity(a, b []uint64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var same int
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / float64(len(a))
+}
+
+// mix is the finalizer of the SplitMix64 generator. It is used to
+// derive independent hash functions from a single shingle hash.
+func mix(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
@@ -0,0 +1,60 @@
+package fingerprint
+
+import (
+	"strings"
+	"testing"
+)
+
+const article = `The quick brown fox jumps over the lazy dog while the farmer
+sleeps in the barn and the sun rises slowly over the quiet hills of the
+valley where nothing much ever happens on an ordinary Tuesday morning`
+
+func TestSimilarPages(t *testing.T) {
+	texts := []string{
+		"Posted on 1 May 2018. " + article,
+		"Posted on 2 May 2018. " + article,
+		"An entirely different page about sitemaps, crawling and BigQuery schemas.",
+	}
+
+	var hashes []uint64
+	var sigs [][]uint64
+	for _, text := range texts {
+		h, ok := SimHash(text)
+		if !ok {
+			t.Fatalf("expected SimHash of %q", text)
+		}
+		hashes = append(hashes, h)
+		sigs = append(sigs, MinHash(text, 64))
+	}
+
+	for name, groups := range map[string][][]int{
+		"simhash": ClusterSimHash(hashes, 0.85),
+		"minhash": ClusterMinHash(sigs, 0.8),
+	} {
+		if len(groups) != 1 || len(groups[0]) != 2 || groups[0][0] != 0 || groups[0][1] != 1 {
+			t.Errorf("%s: expected pages 0 and 1 to be grouped, got %v", name, groups)
+		}
+	}
+}
+
+func TestEmptyText(t *testing.T) {
+	if _, ok := SimHash(strings.Repeat(" ", 10)); ok {
+		t.Errorf("text without words should have no SimHash")
+	}
+	if sig := MinHash("", 16); sig != nil {
+		t.Errorf("text without words should have no MinHash")
+	}
+}
+
+func TestClusterSimHashBounds(t *testing.T) {
+	hashes := []uint64{0, 0, 1, ^uint64(0)}
+
+	// Only identical hashes are grouped at threshold 1.
+	if groups := ClusterSimHash(hashes, 1); len(groups) != 1 || len(groups[0]) != 2 {
+		t.Errorf("expected hashes 0 and 1 to be grouped, got %v", groups)
+	}
+	// Hashes differing in every bit are never grouped.
+	if groups := ClusterSimHash(hashes, 0.02); len(groups) != 1 || len(groups[0]) != 3 {
+		t.Errorf("expected hashes 0, 1 and 2 to be grouped, got %v", groups)
+	}
+}
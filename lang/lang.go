@@ -0,0 +1,192 @@
+// Package lang is an internal package of the tool Crawl, responsible
+// for guessing the language of page text. It uses the character
+// trigram method of Cavnar and Trenkle, with small built-in profiles,
+// so it needs no network access or model files. It is good enough to
+// tell which of a handful of common languages a page is written in,
+// not to distinguish close relatives reliably.
+package lang
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// profileSize is the number of most frequent trigrams compared.
+const profileSize = 300
+
+// minLetters is the least number of letters a text must contain
+// for its language to be guessed.
+const minLetters = 20
+
+// MinConfidence is the least confidence with which a language is
+// reported. Text in a language without a profile is still closest to
+// one of those known, but only by a small margin; so, often, is text
+// in a close relative of another known language.
+const MinConfidence = 0.05
+
+// samples are short passages of typical text in each language, from
+// which the profiles are built. Languages are named by their ISO 639-1
+// codes.
+var samples = map[string]string{
+	"da": `Det er en af de vigtigste opgaver for kommunen at sikre, at alle borgere har adgang til gode tilbud. Vi har derfor valgt at styrke samarbejdet med de lokale foreninger, og det betyder at der kommer flere aktiviteter for børn og unge i løbet af året. Hvis du har spørgsmål, er du velkommen til at kontakte os.`,
+	"de": `Das ist eine der wichtigsten Aufgaben der Stadt, dafür zu sorgen, dass alle Bürger Zugang zu guten Angeboten haben. Wir haben uns deshalb entschieden, die Zusammenarbeit mit den örtlichen Vereinen zu stärken, und das bedeutet, dass es im Laufe des Jahres mehr Aktivitäten für Kinder und Jugendliche gibt. Wenn Sie Fragen haben, können Sie sich gerne an uns wenden.`,
+	"en": `It is one of the most important tasks of the city to make sure that all citizens have access to good services. We have therefore decided to strengthen our work with the local clubs, and this means that there will be more activities for children and young people during the year. If you have any questions, you are welcome to contact us.`,
+	"es": `Es una de las tareas más importantes de la ciudad asegurar que todos los ciudadanos tengan acceso a buenos servicios. Por eso hemos decidido reforzar la colaboración con las asociaciones locales, y esto significa que habrá más actividades para los niños y los jóvenes durante el año. Si tiene alguna pregunta, no dude en ponerse en contacto con nosotros.`,
+	"fr": `C'est l'une des tâches les plus importantes de la ville de veiller à ce que tous les citoyens aient accès à de bons services. Nous avons donc décidé de renforcer notre collaboration avec les associations locales, et cela signifie qu'il y aura plus d'activités pour les enfants et les jeunes pendant l'année. Si vous avez des questions, n'hésitez pas à nous contacter.`,
+	"it": `È uno dei compiti più importanti della città fare in modo che tutti i cittadini abbiano accesso a buoni servizi. Per questo abbiamo deciso di rafforzare la collaborazione con le associazioni locali, e questo significa che ci saranno più attività per i bambini e i giovani durante l'anno. Se avete domande, non esitate a contattarci.`,
+	"nl": `Het is een van de belangrijkste taken van de gemeente om ervoor te zorgen dat alle inwoners toegang hebben tot goede voorzieningen. Daarom hebben we besloten de samenwerking met de lokale verenigingen te versterken, en dat betekent dat er in de loop van het jaar meer activiteiten zijn voor kinderen en jongeren. Als u vragen heeft, kunt u gerust contact met ons opnemen.`,
+	"pl": `Jednym z najważniejszych zadań miasta jest zapewnienie wszystkim mieszkańcom dostępu do dobrych usług. Dlatego postanowiliśmy wzmocnić współpracę z lokalnymi stowarzyszeniami, a to oznacza, że w ciągu roku będzie więcej zajęć dla dzieci i młodzieży. Jeśli masz jakieś pytania, zapraszamy do kontaktu z nami.`,
+	"pt": `É uma das tarefas mais importantes da cidade garantir que todos os cidadãos tenham acesso a bons serviços. Por isso decidimos reforçar a colaboração com as associações locais, e isso significa que haverá mais atividades para as crianças e os jovens durante o ano. Se tiver alguma dúvida, não hesite em entrar em contato conosco.`,
+	"sv": `Det är en av kommunens viktigaste uppgifter att se till att alla invånare har tillgång till bra service. Vi har därför beslutat att stärka samarbetet med de lokala föreningarna, och det innebär att det blir fler aktiviteter för barn och unga under året. Om du har några frågor är du välkommen att kontakta oss.`,
+}
+
+// profiles maps each language to the ranks of its most frequent
+// trigrams. codes lists the languages in order, so that profiles are
+// always compared in the same order.
+var (
+	profiles = make(map[string]map[string]int)
+	codes    []string
+)
+
+func init() {
+	for code, text := range samples {
+		profiles[code] = rank(trigrams(text))
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+}
+
+// Detect returns the ISO 639-1 code of the language text is most
+// likely written in, and a confidence between MinConfidence and 1. If
+// text is too short to judge, or can't be told apart from other
+// languages with at least MinConfidence, as for text in another
+// script or in a language without a profile, it returns "" and 0.
+func Detect(text string) (string, float64) {
+	counts := trigrams(text)
+	var letters int
+	for _, r := range text {
+		if unicode.IsLetter(r) {
+			letters++
+		}
+	}
+	if letters < minLetters || len(counts) == 0 {
+		return "", 0
+	}
+	ranks := rank(counts)
+	if !overlaps(ranks) {
+		return "", 0
+	}
+
+	var best string
+	bestDist, secondDist := -1, -1
+	for _, code := range codes {
+		d := distance(ranks, profiles[code])
+		switch {
+		case bestDist < 0 || d < bestDist:
+			best, secondDist, bestDist = code, bestDist, d
+		case secondDist < 0 || d < secondDist:
+			secondDist = d
+		}
+	}
+	if secondDist <= 0 {
+		return best, 1
+	}
+	// The confidence reflects how much closer the best profile is
+	// than the next best. If it is no closer, the text can't be
+	// told apart.
+	confidence := float64(secondDist-bestDist) / float64(secondDist)
+	if confidence < MinConfidence {
+		return "", 0
+	}
+	return best, confidence
+}
+
+// Known says whether Detect can report the language with the ISO
+// 639-1 code code.
+func Known(code string) bool {
+	_, ok := profiles[code]
+	return ok
+}
+
+// overlaps says whether any of the trigrams ranked in doc appear in
+// any profile.
+func overlaps(doc map[string]int) bool {
+	for g := range doc {
+		for _, profile := range profiles {
+			if _, ok := profile[g]; ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Primary returns the primary language subtag of a language tag, such
+// as "en" for "en-GB" or "en_GB", lower-cased.
+func Primary(tag string) string {
+	tag = strings.TrimSpace(strings.ToLower(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	return tag
+}
+
+// trigrams counts the letter trigrams of text. Each word is padded
+// with spaces, so that trigrams at the start and end of words are
+// distinguished.
+func trigrams(text string) map[string]int {
+	counts := make(map[string]int)
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, w := range words {
+		runes := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			counts[string(runes[i:i+3])]++
+		}
+	}
+	return counts
+}
+
+// rank orders trigrams by descending frequency, and returns the rank
+// of each of the profileSize most frequent.
+func rank(counts map[string]int) map[string]int {
+	grams := make([]string, 0, len(counts))
+	for g := range counts {
+		grams = append(grams, g)
+	}
+	sort.Slice(grams, func(i, j int) bool {
+		if counts[grams[i]] != counts[grams[j]] {
+			return counts[grams[i]] > counts[grams[j]]
+		}
+		return grams[i] < grams[j]
+	})
+	if len(grams) > profileSize {
+		grams = grams[:profileSize]
+	}
+	ranks := make(map[string]int, len(grams))
+	for i, g := range grams {
+		ranks[g] = i
+	}
+	return ranks
+}
+
+// distance is the "out of place" measure between a document's
+// trigram ranks and a language profile.
+func distance(doc, profile map[string]int) int {
+	var d int
+	for g, r := range doc {
+		p, ok := profile[g]
+		if !ok {
+			d += profileSize
+			continue
+		}
+		if r > p {
+			d += r - p
+		} else {
+			d += p - r
+		}
+	}
+	return d
+}
@@ -0,0 +1,60 @@
+package lang
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	for _, tt := range []struct {
+		text string
+		want string
+	}{
+		{"The quick brown fox jumps over the lazy dog, and then it runs back into the forest where it lives with its family.", "en"},
+		{"Der schnelle braune Fuchs springt über den faulen Hund und läuft dann zurück in den Wald, wo er mit seiner Familie lebt.", "de"},
+		{"Le renard brun rapide saute par-dessus le chien paresseux, puis il retourne dans la forêt où il vit avec sa famille.", "fr"},
+		{"El rápido zorro marrón salta sobre el perro perezoso y luego vuelve al bosque donde vive con su familia. Cada mañana sale a buscar comida para sus crías, y por la tarde descansa bajo los árboles cerca del río.", "es"},
+	} {
+		if got, _ := Detect(tt.text); got != tt.want {
+			t.Errorf("expected %s, got %s for %q", tt.want, got, tt.text)
+		}
+	}
+}
+
+func TestTooShort(t *testing.T) {
+	if got, conf := Detect("Hi there"); got != "" || conf != 0 {
+		t.Errorf("short text should not be detected, got %s (%f)", got, conf)
+	}
+}
+
+func TestOtherScripts(t *testing.T) {
+	for _, text := range []string{
+		"東京は日本の首都であり、世界でも有数の大都市です。多くの人々が毎日ここで働いています。",
+		"Москва является столицей России и одним из крупнейших городов мира, где живут миллионы людей.",
+	} {
+		if got, conf := Detect(text); got != "" || conf != 0 {
+			t.Errorf("text in another script should not be detected, got %s (%f) for %q", got, conf, text)
+		}
+	}
+}
+
+func TestLowConfidence(t *testing.T) {
+	for _, text := range []string{
+		"Nopea ruskea kettu hyppää laiskan koiran yli ja juoksee sitten takaisin metsään, jossa se asuu perheensä kanssa.",
+		"Rychlá hnědá liška skáče přes líného psa a pak běží zpět do lesa, kde žije se svou rodinou.",
+		"Home About Contact Login Register Search Menu Blog News Shop Cart Account Privacy Terms Sitemap",
+	} {
+		if got, conf := Detect(text); got != "" || conf != 0 {
+			t.Errorf("text without a clear language should not be detected, got %s (%f) for %q", got, conf, text)
+		}
+	}
+}
+
+func TestKnown(t *testing.T) {
+	if !Known("en") || Known("fi") {
+		t.Errorf("expected en to be known and fi not")
+	}
+}
+
+func TestPrimary(t *testing.T) {
+	if got := Primary(" en_GB "); got != "en" {
+		t.Errorf(`expected "en", got %q`, got)
+	}
+}
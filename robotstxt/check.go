@@ -0,0 +1,163 @@
+package robotstxt
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/benjaminestes/robots"
+)
+
+// DefaultTimeout is the time limit on each request for a robots.txt
+// file.
+const DefaultTimeout = 30 * time.Second
+
+// client requests robots.txt files. Unlike http.DefaultClient, it
+// doesn't wait forever for a slow server.
+var client = &http.Client{Timeout: DefaultTimeout}
+
+// Row is the decision a robots.txt file makes about a URL for a
+// user-agent. Group and Rule are the group that applies to the
+// user-agent and the rule in it that decided, if any, with their line
+// numbers. If the robots.txt file couldn't be read, Status says why.
+// If its rules don't explain the decision, Unexplained is true and
+// no group or rule is given.
+type Row struct {
+	URL         string
+	UserAgent   string
+	Robots      string
+	Status      string `json:",omitempty"`
+	Allowed     bool
+	Unexplained bool   `json:",omitempty"`
+	Group       string `json:",omitempty"`
+	GroupLine   int    `json:",omitempty"`
+	Rule        string `json:",omitempty"`
+	RuleLine    int    `json:",omitempty"`
+
+	// When testing a draft, LiveAllowed is the decision of the
+	// live robots.txt file, and Changed says whether it differs.
+	LiveAllowed *bool `json:",omitempty"`
+	Changed     bool  `json:",omitempty"`
+}
+
+// Source is a robots.txt file, as interpreted by package robots to
+// decide and by File to explain. Location is where it was read from,
+// and Status the status code it was served with.
+type Source struct {
+	Location string
+	Status   int
+
+	allow   func(ua, rawurl string) bool
+	explain *File
+}
+
+// Load reads a robots.txt file from the file name.
+func Load(name string) (*Source, error) {
+	body, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return makeSource(name, http.StatusOK, body)
+}
+
+// Fetch requests the robots.txt file for the site of rawurl. As in a
+// crawl, a robots.txt file that can't be requested is treated as a
+// server error.
+func Fetch(rawurl string) (*Source, error) {
+	location, err := robots.Locate(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Get(location)
+	if err != nil {
+		log.Printf("couldn't retrieve %s: %v", location, err)
+		return makeSource(location, http.StatusServiceUnavailable, nil)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		log.Printf("couldn't read %s: %v", location, err)
+		return makeSource(location, http.StatusServiceUnavailable, nil)
+	}
+	return makeSource(location, resp.StatusCode, body)
+}
+
+func makeSource(location string, status int, body []byte) (*Source, error) {
+	rtxt, err := robots.From(status, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	explain, err := Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	allow := func(ua, rawurl string) bool {
+		return rtxt.Tester(ua)(rawurl)
+	}
+	return &Source{location, status, allow, explain}, nil
+}
+
+// Test decides whether ua may crawl rawurl. The decision is made by
+// package robots, as in a crawl; File only explains it. A non-2xx
+// robots.txt file has no rules to explain.
+func (s *Source) Test(ua, rawurl string) *Row {
+	row := &Row{
+		URL:       rawurl,
+		UserAgent: ua,
+		Robots:    s.Location,
+		Allowed:   s.allow(ua, rawurl),
+	}
+	if s.Status < 200 || s.Status >= 300 {
+		row.Status = fmt.Sprintf("robots.txt returned %d", s.Status)
+		return row
+	}
+	m := s.explain.Match(ua, rawurl)
+	if m.Allowed != row.Allowed {
+		log.Printf("no rule of %s explains whether %s may crawl %s", s.Location, ua, rawurl)
+		row.Unexplained = true
+		return row
+	}
+	if len(m.Groups) > 0 {
+		row.Group = "user-agent: " + m.Agent
+		row.GroupLine = m.Groups[0].Line
+	}
+	if m.Rule != nil {
+		row.Rule = m.Rule.String()
+		row.RuleLine = m.Rule.Line
+	}
+	return row
+}
+
+// Live tests URLs against the live robots.txt files of their sites,
+// requesting each file once. The zero value is ready to use.
+type Live struct {
+	sources map[string]*Source
+}
+
+// Test sets the live fields of row, the decision of a draft, from
+// the live robots.txt file for its URL. If that can't be found, row
+// is left without them.
+func (l *Live) Test(row *Row) error {
+	location, err := robots.Locate(row.URL)
+	if err != nil {
+		return err
+	}
+	if l.sources == nil {
+		l.sources = make(map[string]*Source)
+	}
+	s, ok := l.sources[location]
+	if !ok {
+		s, err = Fetch(row.URL)
+		if err != nil {
+			return err
+		}
+		l.sources[location] = s
+	}
+	allowed := s.Test(row.UserAgent, row.URL).Allowed
+	row.LiveAllowed = &allowed
+	row.Changed = allowed != row.Allowed
+	return nil
+}
@@ -0,0 +1,71 @@
+package robotstxt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestSourceTest(t *testing.T) {
+	s, err := makeSource("robots.txt", http.StatusOK, []byte("User-agent: *\nDisallow: /private/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := s.Test("crawl", "http://example.com/private/a")
+	if row.Allowed || row.Unexplained || row.Group != "user-agent: *" || row.GroupLine != 1 || row.RuleLine != 2 {
+		t.Errorf("unexpected row %+v", row)
+	}
+
+	// A decision the rules of the file don't explain is reported
+	// without a group or rule.
+	s.allow = func(ua, rawurl string) bool { return true }
+	row = s.Test("crawl", "http://example.com/private/a")
+	if !row.Allowed || !row.Unexplained || row.Group != "" || row.Rule != "" {
+		t.Errorf("expected unexplained row, got %+v", row)
+	}
+
+	s, err = makeSource("robots.txt", http.StatusServiceUnavailable, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	row = s.Test("crawl", "http://example.com/")
+	if row.Allowed || row.Status != "robots.txt returned 503" || row.Unexplained {
+		t.Errorf("unexpected row for unavailable robots.txt %+v", row)
+	}
+}
+
+func TestLive(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, "User-agent: *\nDisallow: /old/\n")
+	}))
+	defer ts.Close()
+
+	draft, err := makeSource("draft.txt", http.StatusOK, []byte("User-agent: *\nDisallow: /new/\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var live Live
+	var got []string
+	for _, path := range []string{"/old/a", "/new/a", "/same"} {
+		row := draft.Test("crawl", ts.URL+path)
+		if err := live.Test(row); err != nil {
+			t.Fatal(err)
+		}
+		if row.LiveAllowed == nil {
+			t.Fatalf("expected live decision for %s", path)
+		}
+		got = append(got, fmt.Sprintf("%s %t %t %t", path, row.Allowed, *row.LiveAllowed, row.Changed))
+	}
+	want := "/old/a true false true,/new/a false true true,/same true true false"
+	if strings.Join(got, ",") != want {
+		t.Errorf("expected %s, got %s", want, strings.Join(got, ","))
+	}
+	if requests != 1 {
+		t.Errorf("expected live robots.txt to be requested once, got %d", requests)
+	}
+}
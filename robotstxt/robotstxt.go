@@ -0,0 +1,196 @@
+// Package robotstxt is an internal package of the tool Crawl,
+// responsible for explaining which robots.txt rule applies to a URL.
+// Whether a URL may be crawled is decided by package robots; this
+// package reports why.
+//
+// Specification: https://www.rfc-editor.org/rfc/rfc9309
+package robotstxt
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Rule is an allow or disallow line of a robots.txt file.
+type Rule struct {
+	Allow bool
+	Path  string
+	Line  int
+
+	pattern *regexp.Regexp
+}
+
+func (r *Rule) String() string {
+	if r.Allow {
+		return "allow: " + r.Path
+	}
+	return "disallow: " + r.Path
+}
+
+// Group is a set of rules for the user-agents named at its start.
+// Line is the line of its first user-agent.
+type Group struct {
+	UserAgents []string
+	Rules      []*Rule
+	Line       int
+}
+
+// File is a parsed robots.txt file. Its Sitemap lines are read by
+// sitemap.ParseRobots.
+type File struct {
+	Groups []*Group
+}
+
+// Parse reads a robots.txt file. Lines it doesn't understand are
+// ignored, as are rules that precede any user-agent.
+func Parse(in io.Reader) (*File, error) {
+	f := &File{}
+	var group *Group
+	// inAgents is true while reading the user-agent lines at
+	// the start of a group.
+	var inAgents bool
+
+	scanner := bufio.NewScanner(in)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(line[:i]))
+		value := strings.TrimSpace(line[i+1:])
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				group = &Group{Line: n}
+				f.Groups = append(f.Groups, group)
+				inAgents = true
+			}
+			group.UserAgents = append(group.UserAgents, value)
+		case "allow", "disallow":
+			inAgents = false
+			// An empty path matches nothing.
+			if group == nil || value == "" {
+				continue
+			}
+			group.Rules = append(group.Rules, &Rule{
+				Allow:   key == "allow",
+				Path:    value,
+				Line:    n,
+				pattern: compile(value),
+			})
+		}
+	}
+	return f, scanner.Err()
+}
+
+// compile converts a path pattern, in which * matches any characters
+// and a final $ matches the end of the path, to a regular expression.
+func compile(path string) *regexp.Regexp {
+	end := strings.HasSuffix(path, "$")
+	path = strings.TrimSuffix(path, "$")
+	parts := strings.Split(path, "*")
+	for i := range parts {
+		parts[i] = regexp.QuoteMeta(parts[i])
+	}
+	expr := "^" + strings.Join(parts, ".*")
+	if end {
+		expr += "$"
+	}
+	return regexp.MustCompile(expr)
+}
+
+// Match is the decision a File makes about a URL. Groups are those
+// that apply to the user-agent, and Rule is the rule in them that
+// decides whether the URL may be crawled, if any. Agent is the
+// user-agent named by the first of Groups, as written in the file.
+type Match struct {
+	Allowed bool
+	Agent   string
+	Groups  []*Group
+	Rule    *Rule
+}
+
+// Match decides whether the user-agent ua may crawl rawurl.
+//
+// The groups that apply are those naming the longest user-agent that
+// is a prefix of ua, ignoring case, or else those naming "*". Their
+// rules are combined. The rule with the longest path matching the
+// URL decides; if an allow rule and a disallow rule are equally
+// long, the allow rule decides. If no rule matches, the URL may be
+// crawled.
+func (f *File) Match(ua, rawurl string) *Match {
+	m := &Match{Allowed: true}
+
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return m
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if u.RawQuery != "" {
+		path += "?" + u.RawQuery
+	}
+	// robots.txt itself may always be crawled.
+	if path == "/robots.txt" {
+		return m
+	}
+
+	ua = strings.ToLower(ua)
+	for _, g := range f.Groups {
+		a := g.agent(ua)
+		switch {
+		case a == "":
+			continue
+		case m.Agent == "" || (m.Agent == "*" && a != "*") || len(a) > len(m.Agent):
+			m.Agent, m.Groups = a, []*Group{g}
+		case strings.EqualFold(a, m.Agent):
+			m.Groups = append(m.Groups, g)
+		}
+	}
+
+	for _, g := range m.Groups {
+		for _, r := range g.Rules {
+			if !r.pattern.MatchString(path) {
+				continue
+			}
+			if m.Rule == nil || len(r.Path) > len(m.Rule.Path) || (len(r.Path) == len(m.Rule.Path) && r.Allow) {
+				m.Rule = r
+			}
+		}
+	}
+	if m.Rule != nil {
+		m.Allowed = m.Rule.Allow
+	}
+	return m
+}
+
+// agent returns the longest user-agent of g that is a prefix of ua,
+// which is lower case, ignoring case, or "*" if g applies to all
+// user-agents. The user-agent is returned as written in g. It returns
+// "" if g doesn't apply to ua.
+func (g *Group) agent(ua string) string {
+	var best string
+	for _, agent := range g.UserAgents {
+		switch {
+		case agent == "*":
+			if best == "" {
+				best = agent
+			}
+		case strings.HasPrefix(ua, strings.ToLower(agent)):
+			if best == "" || best == "*" || len(agent) > len(best) {
+				best = agent
+			}
+		}
+	}
+	return best
+}
@@ -0,0 +1,85 @@
+package robotstxt
+
+import (
+	"strings"
+	"testing"
+)
+
+const testRobots = `# Test robots.txt
+User-agent: *
+Disallow: /private/
+Allow: /private/public
+Disallow: /*.pdf$
+
+User-agent: Googlebot
+User-agent: Bingbot
+Disallow: /nogoogle
+Allow: /page
+Disallow: /page
+
+User-agent: Googlebot-Image
+Disallow: /
+
+User-agent: googlebot
+Disallow: /also
+
+Sitemap: https://www.example.com/sitemap.xml
+`
+
+func TestParse(t *testing.T) {
+	f, err := Parse(strings.NewReader(testRobots))
+	if err != nil {
+		t.Fatalf("couldn't parse robots.txt: %v", err)
+	}
+	if len(f.Groups) != 4 {
+		t.Fatalf("expected 4 groups, got %d", len(f.Groups))
+	}
+	if g := f.Groups[1]; len(g.UserAgents) != 2 || g.Line != 7 {
+		t.Errorf("expected 2 user-agents from line 7, got %v from line %d", g.UserAgents, g.Line)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	f, err := Parse(strings.NewReader(testRobots))
+	if err != nil {
+		t.Fatalf("couldn't parse robots.txt: %v", err)
+	}
+
+	tests := []struct {
+		ua      string
+		url     string
+		allowed bool
+		agent   string
+		rule    string
+	}{
+		{"Crawler", "https://www.example.com/", true, "*", ""},
+		{"Crawler", "https://www.example.com/private/x", false, "*", "disallow: /private/"},
+		{"Crawler", "https://www.example.com/private/public", true, "*", "allow: /private/public"},
+		{"Crawler", "https://www.example.com/a/b.pdf", false, "*", "disallow: /*.pdf$"},
+		{"Crawler", "https://www.example.com/a/b.pdf?x=1", true, "*", ""},
+		{"Googlebot/2.1", "https://www.example.com/private/x", true, "Googlebot", ""},
+		{"Googlebot", "https://www.example.com/nogoogle", false, "Googlebot", "disallow: /nogoogle"},
+		{"Googlebot", "https://www.example.com/page", true, "Googlebot", "allow: /page"},
+		{"Googlebot", "https://www.example.com/also", false, "Googlebot", "disallow: /also"},
+		{"Bingbot", "https://www.example.com/also", true, "Bingbot", ""},
+		{"Googlebot-Image", "https://www.example.com/x", false, "Googlebot-Image", "disallow: /"},
+		{"Googlebot-Image", "https://www.example.com/robots.txt", true, "", ""},
+	}
+
+	for _, test := range tests {
+		m := f.Match(test.ua, test.url)
+		if m.Allowed != test.allowed {
+			t.Errorf("%s %s: expected allowed %v, got %v", test.ua, test.url, test.allowed, m.Allowed)
+		}
+		if m.Agent != test.agent {
+			t.Errorf("%s %s: expected agent %q, got %q", test.ua, test.url, test.agent, m.Agent)
+		}
+		var rule string
+		if m.Rule != nil {
+			rule = m.Rule.String()
+		}
+		if rule != test.rule {
+			t.Errorf("%s %s: expected rule %q, got %q", test.ua, test.url, test.rule, rule)
+		}
+	}
+}
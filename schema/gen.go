@@ -4,7 +4,7 @@
 
 // This file generates a struct which, when marshalled into JSON, will
 // be a string representing a valid BigQuery schema. It needs to be re-run
-// only if the data.Result struct changes.
+// only if the data.Result or sitemap.Finding structs change.
 //
 // Update using `go generate gen.go`.
 
@@ -23,6 +23,7 @@ import (
 	"reflect"
 
 	"github.com/benjaminestes/crawl/crawler/data"
+	"github.com/benjaminestes/crawl/sitemap"
 )
 
 // Does this have to be re-defined here?
@@ -59,6 +60,13 @@ func main() {
 	fmt.Fprintln(&buf, "var bq = []schemaItem{")
 	recursiveGenerate(t, &buf)
 	fmt.Fprintln(&buf, "}")
+
+	f := &sitemap.Finding{}
+	t = reflect.TypeOf(*f)
+
+	fmt.Fprintln(&buf, "var bqFinding = []schemaItem{")
+	recursiveGenerate(t, &buf)
+	fmt.Fprintln(&buf, "}")
 	genFile("schema.go", &buf)
 }
 
@@ -79,13 +87,20 @@ func recursiveGenerate(t reflect.Type, buf *bytes.Buffer) {
 
 		switch g.Type.Kind() {
 		case reflect.Ptr:
+			// Pointers to scalars are nullable scalars.
+			if g.Type.Elem().Kind() != reflect.Struct {
+				break
+			}
 			fmt.Fprintln(buf, "Fields: []schemaItem{")
 			recursiveGenerate(g.Type.Elem(), buf)
 			fmt.Fprintln(buf, "},")
 		case reflect.Slice:
-			fmt.Fprintln(buf, "Fields: []schemaItem{")
-			recursiveGenerate(g.Type.Elem().Elem(), buf)
-			fmt.Fprintln(buf, "},")
+			// Repeated scalars like []string have no fields.
+			if g.Type.Elem().Kind() == reflect.Ptr {
+				fmt.Fprintln(buf, "Fields: []schemaItem{")
+				recursiveGenerate(g.Type.Elem().Elem(), buf)
+				fmt.Fprintln(buf, "},")
+			}
 		}
 
 		fmt.Fprintf(buf, "},\n")
@@ -117,7 +132,10 @@ func typeToString(t reflect.Type) string {
 	case reflect.Bool:
 		return "BOOL"
 	case reflect.Ptr:
-		return "RECORD"
+		if t.Elem().Kind() == reflect.Struct {
+			return "RECORD"
+		}
+		return typeToString(t.Elem())
 	case reflect.Slice:
 		return typeToString(t.Elem())
 	default:
@@ -10,3 +10,11 @@ func BigQueryJSON() []byte {
 	j, _ := json.MarshalIndent(bq, "", "\t")
 	return j
 }
+
+// FindingBigQueryJSON is like BigQueryJSON, but describes the
+// findings of crawl sitemap -validate.
+func FindingBigQueryJSON() []byte {
+	// FIXME: Check error
+	j, _ := json.MarshalIndent(bqFinding, "", "\t")
+	return j
+}
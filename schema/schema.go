@@ -47,11 +47,411 @@ var bq = []schemaItem{
 		Type: "INT64",
 		Mode: "REQUIRED",
 	},
+	{
+		Name: "Discovery",
+		Type: "STRING",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "SitemapEntry",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "Sitemap",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Lastmod",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Changefreq",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Priority",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Images",
+				Type: "RECORD",
+				Mode: "REPEATED",
+				Fields: []schemaItem{
+					{
+						Name: "Loc",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Caption",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Title",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "GeoLocation",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "License",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+			{
+				Name: "Videos",
+				Type: "RECORD",
+				Mode: "REPEATED",
+				Fields: []schemaItem{
+					{
+						Name: "ThumbnailLoc",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Title",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Description",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "ContentLoc",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "PlayerLoc",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Duration",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "ExpirationDate",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "PublicationDate",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "FamilyFriendly",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Tags",
+						Type: "STRING",
+						Mode: "REPEATED",
+					},
+				},
+			},
+			{
+				Name: "News",
+				Type: "RECORD",
+				Mode: "NULLABLE",
+				Fields: []schemaItem{
+					{
+						Name: "PublicationName",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "PublicationLanguage",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "PublicationDate",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Title",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Keywords",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+			{
+				Name: "Hreflang",
+				Type: "RECORD",
+				Mode: "REPEATED",
+				Fields: []schemaItem{
+					{
+						Name: "Address",
+						Type: "RECORD",
+						Mode: "NULLABLE",
+						Fields: []schemaItem{
+							{
+								Name: "Full",
+								Type: "STRING",
+								Mode: "NULLABLE",
+							},
+							{
+								Name: "Scheme",
+								Type: "STRING",
+								Mode: "NULLABLE",
+							},
+							{
+								Name: "Opaque",
+								Type: "STRING",
+								Mode: "NULLABLE",
+							},
+							{
+								Name: "Host",
+								Type: "STRING",
+								Mode: "NULLABLE",
+							},
+							{
+								Name: "Path",
+								Type: "STRING",
+								Mode: "NULLABLE",
+							},
+							{
+								Name: "Query",
+								Type: "STRING",
+								Mode: "NULLABLE",
+							},
+						},
+					},
+					{
+						Name: "Href",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Hreflang",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Source",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+		},
+	},
+	{
+		Name: "Base",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "Full",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Scheme",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Opaque",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Host",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Path",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Query",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+	{
+		Name: "MediaType",
+		Type: "STRING",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "MediaTypeSniffed",
+		Type: "BOOL",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "Charset",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "Header",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Meta",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "BOM",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Detected",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+	{
+		Name: "PDF",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "Author",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Creator",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Producer",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "PageCount",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+	{
+		Name: "Language",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "HTMLLang",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "ContentLanguage",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "OGLocale",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Detected",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Confidence",
+				Type: "FLOAT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Mismatch",
+				Type: "BOOL",
+				Mode: "NULLABLE",
+			},
+		},
+	},
 	{
 		Name: "BodyTextHash",
 		Type: "STRING",
 		Mode: "NULLABLE",
 	},
+	{
+		Name: "HTMLSize",
+		Type: "INT64",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "WordCount",
+		Type: "INT64",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "TextRatio",
+		Type: "FLOAT64",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "MainTextHash",
+		Type: "STRING",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "MainWordCount",
+		Type: "INT64",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "MainText",
+		Type: "STRING",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "SimHash",
+		Type: "INT64",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "MinHash",
+		Type: "INT64",
+		Mode: "REPEATED",
+	},
+	{
+		Name: "Indexable",
+		Type: "BOOL",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "NonIndexableReasons",
+		Type: "STRING",
+		Mode: "REPEATED",
+	},
 	{
 		Name: "Description",
 		Type: "STRING",
@@ -67,15 +467,217 @@ var bq = []schemaItem{
 		Type: "STRING",
 		Mode: "NULLABLE",
 	},
+	{
+		Name: "Headings",
+		Type: "RECORD",
+		Mode: "REPEATED",
+		Fields: []schemaItem{
+			{
+				Name: "Level",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Text",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Order",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+	{
+		Name: "HeadingCounts",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "H1",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "H2",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "H3",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "H4",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "H5",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "H6",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+		},
+	},
 	{
 		Name: "Robots",
 		Type: "STRING",
 		Mode: "NULLABLE",
 	},
 	{
-		Name: "Canonical",
+		Name: "Indexability",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "Noindex",
+				Type: "BOOL",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Nofollow",
+				Type: "BOOL",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Noarchive",
+				Type: "BOOL",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Nosnippet",
+				Type: "BOOL",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "MaxSnippet",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "UnavailableAfter",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Directives",
+				Type: "RECORD",
+				Mode: "REPEATED",
+				Fields: []schemaItem{
+					{
+						Name: "Source",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "UserAgent",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Content",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Noindex",
+						Type: "BOOL",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Nofollow",
+						Type: "BOOL",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Noarchive",
+						Type: "BOOL",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Nosnippet",
+						Type: "BOOL",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "MaxSnippet",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "UnavailableAfter",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+		},
+	},
+	{
+		Name: "Canonical",
+		Type: "RECORD",
+		Mode: "NULLABLE",
+		Fields: []schemaItem{
+			{
+				Name: "Address",
+				Type: "RECORD",
+				Mode: "NULLABLE",
+				Fields: []schemaItem{
+					{
+						Name: "Full",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Scheme",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Opaque",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Host",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Path",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Query",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+			{
+				Name: "Href",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Source",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+	{
+		Name: "Canonicals",
 		Type: "RECORD",
-		Mode: "NULLABLE",
+		Mode: "REPEATED",
 		Fields: []schemaItem{
 			{
 				Name: "Address",
@@ -119,6 +721,11 @@ var bq = []schemaItem{
 				Type: "STRING",
 				Mode: "NULLABLE",
 			},
+			{
+				Name: "Source",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
 		},
 	},
 	{
@@ -178,6 +785,46 @@ var bq = []schemaItem{
 				Type: "BOOL",
 				Mode: "NULLABLE",
 			},
+			{
+				Name: "Rel",
+				Type: "STRING",
+				Mode: "REPEATED",
+			},
+			{
+				Name: "Target",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Title",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Hreflang",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Type",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Context",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Path",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Position",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
 		},
 	},
 	{
@@ -232,8 +879,132 @@ var bq = []schemaItem{
 				Type: "STRING",
 				Mode: "NULLABLE",
 			},
+			{
+				Name: "Source",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+	{
+		Name: "Resources",
+		Type: "RECORD",
+		Mode: "REPEATED",
+		Fields: []schemaItem{
+			{
+				Name: "Address",
+				Type: "RECORD",
+				Mode: "NULLABLE",
+				Fields: []schemaItem{
+					{
+						Name: "Full",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Scheme",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Opaque",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Host",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Path",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Query",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+			{
+				Name: "Href",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Tag",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Attribute",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Rel",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Alt",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "HasAlt",
+				Type: "BOOL",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Width",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Height",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Loading",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Status",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "StatusCode",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "ContentType",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "ContentLength",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
 		},
 	},
+	{
+		Name: "CanonicalConflict",
+		Type: "BOOL",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "HreflangConflict",
+		Type: "BOOL",
+		Mode: "NULLABLE",
+	},
 	{
 		Name: "Status",
 		Type: "STRING",
@@ -313,4 +1084,85 @@ var bq = []schemaItem{
 			},
 		},
 	},
+	{
+		Name: "ClientRedirects",
+		Type: "RECORD",
+		Mode: "REPEATED",
+		Fields: []schemaItem{
+			{
+				Name: "Address",
+				Type: "RECORD",
+				Mode: "NULLABLE",
+				Fields: []schemaItem{
+					{
+						Name: "Full",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Scheme",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Opaque",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Host",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Path",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+					{
+						Name: "Query",
+						Type: "STRING",
+						Mode: "NULLABLE",
+					},
+				},
+			},
+			{
+				Name: "Href",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Type",
+				Type: "STRING",
+				Mode: "NULLABLE",
+			},
+			{
+				Name: "Delay",
+				Type: "INT64",
+				Mode: "NULLABLE",
+			},
+		},
+	},
+}
+var bqFinding = []schemaItem{
+	{
+		Name: "Sitemap",
+		Type: "STRING",
+		Mode: "REQUIRED",
+	},
+	{
+		Name: "Loc",
+		Type: "STRING",
+		Mode: "NULLABLE",
+	},
+	{
+		Name: "Issue",
+		Type: "STRING",
+		Mode: "REQUIRED",
+	},
+	{
+		Name: "Detail",
+		Type: "STRING",
+		Mode: "NULLABLE",
+	},
 }
@@ -0,0 +1,157 @@
+package scrape
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// The main content extractor is a simplified version of the
+// Readability algorithm. Every paragraph-like element scores points
+// for its parent and grandparent according to how much prose it
+// contains. Candidates are then adjusted for their tag, their class
+// and id, and the proportion of their text that is in links, and
+// the highest scoring candidate is taken to be the main content.
+
+var (
+	positiveNames = regexp.MustCompile(`(?i)article|body|content|entry|main|page|post|story|text`)
+	negativeNames = regexp.MustCompile(`(?i)banner|breadcrumb|comment|consent|cookie|footer|header|menu|modal|nav|popup|related|share|sidebar|social|sponsor|widget`)
+)
+
+// boilerplate is the set of elements whose contents are never
+// considered part of the main content.
+var boilerplate = map[atom.Atom]bool{
+	atom.Nav:    true,
+	atom.Header: true,
+	atom.Footer: true,
+	atom.Aside:  true,
+	atom.Form:   true,
+}
+
+// paragraphs is the set of elements whose text is scored.
+var paragraphs = map[atom.Atom]bool{
+	atom.P:          true,
+	atom.Pre:        true,
+	atom.Td:         true,
+	atom.Blockquote: true,
+	atom.Li:         true,
+}
+
+// MainContent returns the element of the tree n that most likely
+// holds its main content, as opposed to navigation, banners and other
+// boilerplate. If no element stands out, it returns the body element,
+// or n itself if there is none.
+func MainContent(n *html.Node) *html.Node {
+	scores := make(map[*html.Node]float64)
+
+	var walk func(*html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			if invisible[node.DataAtom] || boilerplate[node.DataAtom] {
+				return
+			}
+			if paragraphs[node.DataAtom] {
+				scoreParagraph(node, scores)
+			}
+		}
+		for next := node.FirstChild; next != nil; next = next.NextSibling {
+			walk(next)
+		}
+	}
+	walk(n)
+
+	// Candidates are compared in document order, so that ties
+	// are always broken in favor of the first.
+	var best *html.Node
+	var bestScore float64
+	var compare func(*html.Node)
+	compare = func(node *html.Node) {
+		if score, ok := scores[node]; ok {
+			score *= 1 - linkDensity(node)
+			if best == nil || score > bestScore {
+				best, bestScore = node, score
+			}
+		}
+		for next := node.FirstChild; next != nil; next = next.NextSibling {
+			compare(next)
+		}
+	}
+	compare(n)
+	if best == nil {
+		if body := Query("body", nil, n); body != nil {
+			return body
+		}
+		return n
+	}
+	return best
+}
+
+// scoreParagraph adds points for the paragraph p to its parent and,
+// at half weight, its grandparent.
+func scoreParagraph(p *html.Node, scores map[*html.Node]float64) {
+	text := strings.TrimSpace(VisibleText(p))
+	if len(text) < 25 {
+		return
+	}
+	points := 1 + float64(strings.Count(text, ","))
+	if extra := float64(len(text) / 100); extra < 3 {
+		points += extra
+	} else {
+		points += 3
+	}
+
+	weight := 1.0
+	for ancestor := p.Parent; ancestor != nil && weight >= 0.5; ancestor = ancestor.Parent {
+		if ancestor.Type != html.ElementNode {
+			continue
+		}
+		if _, ok := scores[ancestor]; !ok {
+			scores[ancestor] = initialScore(ancestor)
+		}
+		scores[ancestor] += points * weight
+		weight /= 2
+	}
+}
+
+// initialScore judges a candidate by its tag, class and id.
+func initialScore(n *html.Node) float64 {
+	var score float64
+	switch n.DataAtom {
+	case atom.Article, atom.Main:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+	for _, key := range []string{"class", "id"} {
+		name := Attribute(key, n)
+		if negativeNames.MatchString(name) {
+			score -= 25
+		}
+		if positiveNames.MatchString(name) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity returns the fraction of the text of n that is the text
+// of links.
+func linkDensity(n *html.Node) float64 {
+	total := len(VisibleText(n))
+	if total == 0 {
+		return 0
+	}
+	var linked int
+	for _, a := range NodesByTagName("a", n) {
+		linked += len(VisibleText(a))
+	}
+	return float64(linked) / float64(total)
+}
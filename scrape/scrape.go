@@ -47,6 +47,26 @@ func NodesByTagName(tag string, node *html.Node) []*html.Node {
 	return find(node)
 }
 
+// NodesByTagNames is like NodesByTagName, but it finds elements with
+// any of the given tag names. They are returned in document order.
+func NodesByTagNames(tags []string, node *html.Node) []*html.Node {
+	atoms := make(map[atom.Atom]bool)
+	for _, tag := range tags {
+		atoms[atom.Lookup([]byte(tag))] = true
+	}
+	var find func(*html.Node) []*html.Node
+	find = func(node *html.Node) (list []*html.Node) {
+		if node.Type == html.ElementNode && atoms[node.DataAtom] {
+			list = append(list, node)
+		}
+		for next := node.FirstChild; next != nil; next = next.NextSibling {
+			list = append(list, find(next)...)
+		}
+		return
+	}
+	return find(node)
+}
+
 func NodesByName(name string, node *html.Node) []*html.Node {
 	var list []*html.Node
 	if matchAttribute("name", name, node) {
@@ -93,6 +113,20 @@ func Attribute(key string, n *html.Node) string {
 	return ""
 }
 
+// HasAttribute reports whether n has an attribute named key, even
+// if its value is empty.
+func HasAttribute(key string, n *html.Node) bool {
+	if n == nil {
+		return false
+	}
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
 func Classes(node *html.Node) []string {
 	return strings.Fields(Attribute("class", node))
 }
@@ -116,6 +150,53 @@ func Text(n *html.Node) string {
 	return b.String()
 }
 
+// VisibleText is like Text, but it skips the contents of elements
+// that are not rendered as text, like script and style.
+func VisibleText(n *html.Node) string {
+	var b strings.Builder
+	var getTextHelp func(node *html.Node)
+	getTextHelp = func(node *html.Node) {
+		switch {
+		case node == nil:
+			// Do nothing.
+		case node.Type == html.TextNode:
+			b.WriteString(node.Data)
+		case node.Type == html.ElementNode && invisible[node.DataAtom]:
+			// Skip.
+		default:
+			for next := node.FirstChild; next != nil; next = next.NextSibling {
+				getTextHelp(next)
+			}
+		}
+	}
+	getTextHelp(n)
+	return b.String()
+}
+
+// invisible is the set of elements whose text content is not
+// displayed to a reader.
+var invisible = map[atom.Atom]bool{
+	atom.Script:   true,
+	atom.Style:    true,
+	atom.Noscript: true,
+	atom.Template: true,
+}
+
+// Path returns the names of the elements from the root of the tree
+// containing n down to n itself, separated by " > ".
+func Path(n *html.Node) string {
+	var names []string
+	for ; n != nil; n = n.Parent {
+		if n.Type == html.ElementNode {
+			names = append(names, n.Data)
+		}
+	}
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, " > ")
+}
+
 // The following functions are simple predicates that relay whether
 // their arguments match the provided criteria.
 
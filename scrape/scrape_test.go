@@ -2,6 +2,7 @@ package scrape
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"golang.org/x/net/html"
@@ -26,3 +27,79 @@ func TestQuery(t *testing.T) {
 		t.Errorf(`expected string "Match this.", got %s`, txt)
 	}
 }
+
+func TestPath(t *testing.T) {
+	f, err := os.Open("testdata/simple.html")
+	if err != nil {
+		t.Errorf("couldn't open test data")
+	}
+
+	doc, err := html.Parse(f)
+	if err != nil {
+		t.Errorf("couldn't parse test data")
+	}
+
+	n := NodeByID("best-h1", doc)
+
+	if path := Path(n); path != "html > body > h1" {
+		t.Errorf(`expected path "html > body > h1", got %s`, path)
+	}
+}
+
+func TestNodesByTagNames(t *testing.T) {
+	f, err := os.Open("testdata/simple.html")
+	if err != nil {
+		t.Errorf("couldn't open test data")
+	}
+
+	doc, err := html.Parse(f)
+	if err != nil {
+		t.Errorf("couldn't parse test data")
+	}
+
+	nodes := NodesByTagNames([]string{"h1", "p"}, doc)
+
+	var tags []string
+	for _, n := range nodes {
+		tags = append(tags, n.Data)
+	}
+	if got := strings.Join(tags, ","); got != "h1,p,p,h1" {
+		t.Errorf(`expected elements "h1,p,p,h1", got %s`, got)
+	}
+}
+
+func TestMainContent(t *testing.T) {
+	f, err := os.Open("testdata/article.html")
+	if err != nil {
+		t.Errorf("couldn't open test data")
+	}
+
+	doc, err := html.Parse(f)
+	if err != nil {
+		t.Errorf("couldn't parse test data")
+	}
+
+	n := MainContent(doc)
+
+	if id := Attribute("id", n); id != "main-content" {
+		t.Errorf(`expected element with id "main-content", got %s`, Path(n))
+	}
+}
+
+func TestMainContentTie(t *testing.T) {
+	const para = `<p>This paragraph is long enough, with commas, to be scored as prose.</p>`
+	doc, err := html.Parse(strings.NewReader(`<html><body>
+<div id="first">` + para + `</div>
+<div id="second">` + para + `</div>
+</body></html>`))
+	if err != nil {
+		t.Fatalf("couldn't parse test data")
+	}
+
+	// Equal candidates must always be broken the same way.
+	for i := 0; i < 20; i++ {
+		if id := Attribute("id", MainContent(doc)); id != "first" {
+			t.Fatalf(`expected element with id "first", got %q`, id)
+		}
+	}
+}
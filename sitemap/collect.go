@@ -0,0 +1,64 @@
+package sitemap
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+)
+
+// Collector gathers the pages that belong in a sitemap from the
+// results of a crawl. The zero value is an empty Collector.
+type Collector struct {
+	Entries []*Entry
+
+	seen map[string]bool
+}
+
+// Add records the page described by r if it belongs in a sitemap.
+// Indexable pages return 200 and are not canonicalized elsewhere.
+// Each page is recorded once, with the time given by its
+// Last-Modified header as Lastmod.
+func (c *Collector) Add(r *data.Result) {
+	if r.Address == nil || r.StatusCode != 200 || !r.Indexable || c.seen[r.Address.Full] {
+		return
+	}
+	if c.seen == nil {
+		c.seen = make(map[string]bool)
+	}
+	c.seen[r.Address.Full] = true
+	c.Entries = append(c.Entries, &Entry{
+		Loc:     r.Address.Full,
+		Lastmod: lastModified(r),
+	})
+}
+
+// Under returns the entries whose URLs are under base, the URL of the
+// directory a sitemap is published in, and the number that aren't.
+// A sitemap may only list URLs under that directory, and so on the
+// same host.
+func Under(entries []*Entry, base string) ([]*Entry, int) {
+	var listed []*Entry
+	for _, e := range entries {
+		if strings.HasPrefix(e.Loc, base) {
+			listed = append(listed, e)
+		}
+	}
+	return listed, len(entries) - len(listed)
+}
+
+// lastModified returns the time given by the Last-Modified header of
+// the response described by r in the W3C Datetime format, or "" if
+// there is none.
+func lastModified(r *data.Result) string {
+	for _, h := range r.Header {
+		if http.CanonicalHeaderKey(h.K) != "Last-Modified" {
+			continue
+		}
+		if t, err := http.ParseTime(h.V); err == nil {
+			return t.UTC().Format(time.RFC3339)
+		}
+	}
+	return ""
+}
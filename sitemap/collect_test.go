@@ -0,0 +1,63 @@
+package sitemap
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+)
+
+func TestCollector(t *testing.T) {
+	page := func(addr string, status int, reasons ...string) *data.Result {
+		r := &data.Result{}
+		r.Address = data.MakeAddress(addr)
+		r.StatusCode = status
+		r.Indexable = len(reasons) == 0
+		r.NonIndexableReasons = reasons
+		return r
+	}
+
+	ok := page("http://example.com/ok", 200)
+	ok.Header = []*data.Pair{{K: "last-modified", V: "Wed, 21 Oct 2015 07:28:00 GMT"}}
+	badDate := page("http://example.com/bad-date", 200)
+	badDate.Header = []*data.Pair{{K: "Last-Modified", V: "yesterday"}}
+
+	var c Collector
+	for _, r := range []*data.Result{
+		ok,
+		page("http://example.com/missing", 404, data.ReasonStatus),
+		page("http://example.com/copy", 200, data.ReasonCanonicalized),
+		page("http://example.com/ok", 200),
+		badDate,
+		{},
+	} {
+		c.Add(r)
+	}
+
+	var got []string
+	for _, e := range c.Entries {
+		got = append(got, e.Loc+" "+e.Lastmod)
+	}
+	want := "[http://example.com/ok 2015-10-21T07:28:00Z http://example.com/bad-date ]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("expected %s, got %v", want, got)
+	}
+}
+
+func TestUnder(t *testing.T) {
+	entries := []*Entry{
+		{Loc: "http://example.com/blog/a"},
+		{Loc: "http://example.com/shop/b"},
+		{Loc: "https://example.com/blog/c"},
+		{Loc: "http://other.com/blog/d"},
+		{Loc: "http://example.com/blog/e"},
+	}
+
+	listed, skipped := Under(entries, "http://example.com/blog/")
+	if len(listed) != 2 || listed[0].Loc != "http://example.com/blog/a" || listed[1].Loc != "http://example.com/blog/e" {
+		t.Errorf("unexpected entries under base %v", locs(listed))
+	}
+	if skipped != 3 {
+		t.Errorf("expected 3 entries skipped, got %d", skipped)
+	}
+}
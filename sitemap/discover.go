@@ -0,0 +1,119 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/url"
+	"strings"
+)
+
+// WellKnownPaths are the paths at which sites commonly publish a
+// sitemap or index without listing it in robots.txt.
+var WellKnownPaths = []string{
+	"/sitemap.xml",
+	"/sitemap_index.xml",
+	"/sitemap-index.xml",
+	"/sitemap.xml.gz",
+}
+
+// ParseRobots returns the URLs listed on Sitemap lines of the
+// robots.txt file in.
+func ParseRobots(in io.Reader) []string {
+	var sitemaps []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		i := strings.Index(line, ":")
+		if i < 0 {
+			continue
+		}
+		if !strings.EqualFold(strings.TrimSpace(line[:i]), "sitemap") {
+			continue
+		}
+		if loc := strings.TrimSpace(line[i+1:]); loc != "" {
+			sitemaps = append(sitemaps, loc)
+		}
+	}
+	return sitemaps
+}
+
+// WellKnown returns the URLs of WellKnownPaths on the site of rawurl.
+func WellKnown(rawurl string) ([]string, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	var urls []string
+	for _, path := range WellKnownPaths {
+		root := url.URL{Scheme: u.Scheme, Host: u.Host, Path: path}
+		urls = append(urls, root.String())
+	}
+	return urls, nil
+}
+
+// Discovered is a sitemap or index found by Discover or Probe. If it
+// was read to find out whether it exists, its content is kept, so
+// that FetchDiscovered needn't request it again.
+type Discovered struct {
+	URL string
+
+	content *fetched
+}
+
+func (d *Discovered) String() string {
+	return d.URL
+}
+
+// Discover returns the sitemaps of the site of rawurl: those listed
+// in its robots.txt file, followed by those found at
+// WellKnownPaths. Each is listed once.
+func (f *Fetcher) Discover(rawurl string) ([]*Discovered, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	robots := url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/robots.txt"}
+
+	var listed []string
+	if data, err := f.get(robots.String()); err == nil {
+		listed = ParseRobots(bytes.NewReader(data))
+	}
+
+	candidates, err := WellKnown(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return f.Probe(listed, candidates), nil
+}
+
+// Probe returns listed, followed by the URLs in candidates that can
+// be fetched and parsed as a sitemap or index. Each is listed once.
+// The candidates found are returned with their content.
+func (f *Fetcher) Probe(listed, candidates []string) []*Discovered {
+	var sitemaps []*Discovered
+	seen := make(map[string]bool)
+	for _, s := range listed {
+		if !seen[s] {
+			seen[s] = true
+			sitemaps = append(sitemaps, &Discovered{URL: s})
+		}
+	}
+
+	var unseen []string
+	for _, s := range candidates {
+		if !seen[s] {
+			seen[s] = true
+			unseen = append(unseen, s)
+		}
+	}
+	for i, res := range f.fetchLevel(unseen) {
+		if res.err == nil && (len(res.entries) > 0 || len(res.sitemaps) > 0) {
+			sitemaps = append(sitemaps, &Discovered{unseen[i], res})
+		}
+	}
+	return sitemaps
+}
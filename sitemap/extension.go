@@ -0,0 +1,88 @@
+package sitemap
+
+import "strings"
+
+// These types represent the image, video and news extensions to the
+// sitemap protocol, and alternate language versions declared with
+// xhtml:link elements. Elements are matched by their local names, so
+// a sitemap that misdeclares an extension's namespace is still read.
+//
+// Specifications:
+// https://developers.google.com/search/docs/crawling-indexing/sitemaps/image-sitemaps
+// https://developers.google.com/search/docs/crawling-indexing/sitemaps/video-sitemaps
+// https://developers.google.com/search/docs/crawling-indexing/sitemaps/news-sitemap
+// https://developers.google.com/search/docs/specialty/international/localized-versions#sitemap
+
+// Image is an image:image element.
+type Image struct {
+	Loc         string `xml:"loc"`
+	Caption     string `xml:"caption"`
+	Title       string `xml:"title"`
+	GeoLocation string `xml:"geo_location"`
+	License     string `xml:"license"`
+}
+
+// Video is a video:video element.
+type Video struct {
+	ThumbnailLoc    string   `xml:"thumbnail_loc"`
+	Title           string   `xml:"title"`
+	Description     string   `xml:"description"`
+	ContentLoc      string   `xml:"content_loc"`
+	PlayerLoc       string   `xml:"player_loc"`
+	Duration        string   `xml:"duration"`
+	ExpirationDate  string   `xml:"expiration_date"`
+	PublicationDate string   `xml:"publication_date"`
+	FamilyFriendly  string   `xml:"family_friendly"`
+	Tags            []string `xml:"tag"`
+}
+
+// News is a news:news element.
+type News struct {
+	PublicationName     string `xml:"publication>name"`
+	PublicationLanguage string `xml:"publication>language"`
+	PublicationDate     string `xml:"publication_date"`
+	Title               string `xml:"title"`
+	Keywords            string `xml:"keywords"`
+}
+
+// Alternate is an xhtml:link element. In a sitemap, one with Rel
+// "alternate" declares a version of the URL in the language given by
+// Hreflang.
+type Alternate struct {
+	Rel      string `xml:"rel,attr"`
+	Hreflang string `xml:"hreflang,attr"`
+	Href     string `xml:"href,attr"`
+}
+
+// trim removes the whitespace surrounding the values of e, which
+// sitemaps often include for legibility.
+func (e *Entry) trim() {
+	for _, s := range []*string{&e.Loc, &e.Lastmod, &e.Changefreq, &e.Priority} {
+		*s = strings.TrimSpace(*s)
+	}
+	for _, img := range e.Images {
+		for _, s := range []*string{&img.Loc, &img.Caption, &img.Title, &img.GeoLocation, &img.License} {
+			*s = strings.TrimSpace(*s)
+		}
+	}
+	for _, v := range e.Videos {
+		for _, s := range []*string{&v.ThumbnailLoc, &v.Title, &v.Description, &v.ContentLoc,
+			&v.PlayerLoc, &v.Duration, &v.ExpirationDate, &v.PublicationDate, &v.FamilyFriendly} {
+			*s = strings.TrimSpace(*s)
+		}
+		for i := range v.Tags {
+			v.Tags[i] = strings.TrimSpace(v.Tags[i])
+		}
+	}
+	if n := e.News; n != nil {
+		for _, s := range []*string{&n.PublicationName, &n.PublicationLanguage,
+			&n.PublicationDate, &n.Title, &n.Keywords} {
+			*s = strings.TrimSpace(*s)
+		}
+	}
+	for _, a := range e.Alternates {
+		for _, s := range []*string{&a.Rel, &a.Hreflang, &a.Href} {
+			*s = strings.TrimSpace(*s)
+		}
+	}
+}
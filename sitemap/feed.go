@@ -0,0 +1,136 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+)
+
+// Besides XML sitemaps, the protocol accepts RSS and Atom feeds, and
+// plain text files listing one URL per line, which need no parser.
+//
+// Specification: https://www.sitemaps.org/protocol.html#otherformats
+
+type rss struct {
+	// RSS 2.0 items are children of channel; RSS 1.0 items are
+	// children of the root element.
+	Items   []*rssItem `xml:"channel>item"`
+	RDFItem []*rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Link    string `xml:"link"`
+	PubDate string `xml:"pubDate"`
+	Date    string `xml:"date"` // Dublin Core, common in RSS 1.0
+}
+
+type atom struct {
+	Entries []*atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Links   []*atomLink `xml:"link"`
+	Updated string      `xml:"updated"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+// ParseRSS interprets in as an RSS feed. It returns an entry for the
+// link of each item, with its publication date as Lastmod.
+func ParseRSS(in io.Reader) ([]*Entry, error) {
+	data, err := readAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &rss{}
+	err = xml.Unmarshal(data, res)
+	if err != nil {
+		return nil, fmt.Errorf("ParseRSS failed to unmarshal feed data: %v", err)
+	}
+
+	var entries []*Entry
+	for _, item := range append(res.Items, res.RDFItem...) {
+		loc := strings.TrimSpace(item.Link)
+		if loc == "" {
+			continue
+		}
+		date := item.PubDate
+		if date == "" {
+			date = item.Date
+		}
+		entries = append(entries, &Entry{Loc: loc, Lastmod: feedDate(date)})
+	}
+	return entries, nil
+}
+
+// ParseAtom interprets in as an Atom feed. It returns an entry for
+// the alternate link of each feed entry, with its update time as
+// Lastmod.
+func ParseAtom(in io.Reader) ([]*Entry, error) {
+	data, err := readAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &atom{}
+	err = xml.Unmarshal(data, res)
+	if err != nil {
+		return nil, fmt.Errorf("ParseAtom failed to unmarshal feed data: %v", err)
+	}
+
+	var entries []*Entry
+	for _, e := range res.Entries {
+		for _, l := range e.Links {
+			// A link without rel is an alternate link.
+			if l.Rel != "" && l.Rel != "alternate" {
+				continue
+			}
+			if loc := strings.TrimSpace(l.Href); loc != "" {
+				entries = append(entries, &Entry{Loc: loc, Lastmod: feedDate(e.Updated)})
+				break
+			}
+		}
+	}
+	return entries, nil
+}
+
+// readAll reads in, decompressing it if necessary.
+func readAll(in io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(data)
+}
+
+// feedDates lists the layouts of dates in feeds. RSS uses RFC 822
+// dates, often written with a four-digit year; Atom and Dublin Core
+// use RFC 3339 dates.
+var feedDates = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	time.RFC3339,
+}
+
+// feedDate returns s in the W3C Datetime format of a sitemap, or ""
+// if it isn't a date.
+func feedDate(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedDates {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format(time.RFC3339)
+		}
+	}
+	return ""
+}
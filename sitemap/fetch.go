@@ -0,0 +1,253 @@
+package sitemap
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// These errors are reported for sitemaps, or parts of sitemaps, that
+// a Fetcher chose not to read.
+var (
+	ErrRepeated = errors.New("sitemap listed more than once")
+	ErrMaxDepth = errors.New("sitemap index nested too deeply")
+	ErrMaxURLs  = errors.New("URL limit reached")
+)
+
+// Error records a problem with one sitemap encountered by a Fetcher.
+type Error struct {
+	Sitemap string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("sitemap %s: %v", e.Sitemap, e.Err)
+}
+
+// These are the limits of a Fetcher used by callers that don't set
+// their own.
+const (
+	DefaultMaxDepth = 5
+	DefaultMaxURLs  = LimitURLs
+)
+
+// DefaultTimeout is the time limit on each request made by a Fetcher
+// without a Client.
+const DefaultTimeout = 30 * time.Second
+
+// defaultClient is used by a Fetcher without a Client. Unlike
+// http.DefaultClient, it doesn't wait forever for a slow server.
+var defaultClient = &http.Client{Timeout: DefaultTimeout}
+
+// Fetcher retrieves a sitemap, or a sitemap index and the sitemaps it
+// lists, recursively. The zero value is a Fetcher with no limits that
+// requests one sitemap at a time, giving up on each after
+// DefaultTimeout.
+type Fetcher struct {
+	Client    *http.Client
+	UserAgent string
+	Header    http.Header
+
+	// Connections is the maximum number of sitemaps requested at
+	// once.
+	Connections int
+
+	// MaxDepth is the maximum number of indexes that may enclose a
+	// sitemap, and MaxURLs is the maximum number of URLs
+	// collected. If either is 0, there is no limit.
+	MaxDepth int
+	MaxURLs  int
+
+	// If Validator is not nil, every sitemap and index read is
+	// checked by it.
+	Validator *Validator
+}
+
+// fetched is the content of one sitemap or index of size bytes.
+type fetched struct {
+	entries  []*Entry
+	sitemaps []string
+	index    bool
+	size     int
+	err      error
+}
+
+// FetchAll returns the entries of all sitemaps represented by the
+// sitemap or index at url. Sitemaps are requested level by level, so
+// the entries are in the order in which they were listed. A problem
+// with one sitemap is reported among the returned errors, each of
+// which is an *Error, and does not prevent the others from being
+// fetched.
+func (f *Fetcher) FetchAll(url string) ([]*Entry, []error) {
+	return f.FetchDiscovered(&Discovered{URL: url})
+}
+
+// FetchDiscovered is like FetchAll, but if d was read when it was
+// discovered, it isn't requested again.
+func (f *Fetcher) FetchDiscovered(d *Discovered) ([]*Entry, []error) {
+	var entries []*Entry
+	var errs []error
+
+	seen := map[string]bool{d.URL: true}
+	level := []string{d.URL}
+	for depth := 0; len(level) > 0; depth++ {
+		var results []*fetched
+		if depth == 0 && d.content != nil {
+			results = []*fetched{d.content}
+		} else {
+			results = f.fetchLevel(level)
+		}
+
+		var next []string
+		for i, res := range results {
+			if res.err != nil {
+				errs = append(errs, &Error{level[i], res.err})
+				continue
+			}
+			if f.Validator != nil {
+				if res.index {
+					f.Validator.checkIndex(level[i], res.size, res.sitemaps)
+				} else {
+					f.Validator.checkSitemap(level[i], res.size, res.entries)
+				}
+			}
+			for _, e := range res.entries {
+				if f.MaxURLs > 0 && len(entries) >= f.MaxURLs {
+					errs = append(errs, &Error{level[i], ErrMaxURLs})
+					break
+				}
+				entries = append(entries, e)
+			}
+			for _, s := range res.sitemaps {
+				switch {
+				case seen[s]:
+					errs = append(errs, &Error{s, ErrRepeated})
+				case f.MaxDepth > 0 && depth >= f.MaxDepth:
+					errs = append(errs, &Error{s, ErrMaxDepth})
+				default:
+					seen[s] = true
+					next = append(next, s)
+				}
+			}
+		}
+
+		if f.MaxURLs > 0 && len(entries) >= f.MaxURLs {
+			for _, s := range next {
+				errs = append(errs, &Error{s, ErrMaxURLs})
+			}
+			break
+		}
+		level = next
+	}
+
+	return entries, errs
+}
+
+// fetchLevel requests every sitemap in urls, with no more than
+// f.Connections requests active at once.
+func (f *Fetcher) fetchLevel(urls []string) []*fetched {
+	conns := f.Connections
+	if conns < 1 {
+		conns = 1
+	}
+	sem := make(chan bool, conns)
+
+	results := make([]*fetched, len(urls))
+	var wg sync.WaitGroup
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			sem <- true
+			results[i] = f.fetch(url)
+			<-sem
+		}(i, url)
+	}
+	wg.Wait()
+	return results
+}
+
+// fetch requests url and interprets it as an index if its root
+// element is <sitemapindex>, and otherwise as a sitemap.
+func (f *Fetcher) fetch(url string) *fetched {
+	data, err := f.get(url)
+	if err != nil {
+		return &fetched{err: err}
+	}
+
+	if root(data) == "sitemapindex" {
+		sitemaps, err := ParseIndex(bytes.NewReader(data))
+		if err != nil {
+			return &fetched{err: err}
+		}
+		for i := range sitemaps {
+			sitemaps[i] = strings.TrimSpace(sitemaps[i])
+		}
+		return &fetched{sitemaps: sitemaps, index: true, size: len(data)}
+	}
+
+	entries, err := ParseEntries(bytes.NewReader(data))
+	if err != nil {
+		return &fetched{err: err}
+	}
+	for _, e := range entries {
+		e.Sitemap = url
+	}
+	return &fetched{entries: entries, size: len(data)}
+}
+
+// root returns the local name of the root element of the XML
+// document data, or "" if it can't be read.
+func root(data []byte) string {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return ""
+		}
+		if el, ok := tok.(xml.StartElement); ok {
+			return el.Name.Local
+		}
+	}
+}
+
+// get returns the decompressed body of url.
+func (f *Fetcher) get(url string) ([]byte, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range f.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if f.UserAgent != "" {
+		req.Header.Set("User-Agent", f.UserAgent)
+	}
+
+	client := f.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	data, err := readLimited(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return decompress(data)
+}
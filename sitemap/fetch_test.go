@@ -0,0 +1,147 @@
+package sitemap
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// indexServer serves an index at / listing /a.xml, /nested.xml,
+// /missing.xml and itself. /nested.xml is an index listing /b.xml.
+func indexServer(t *testing.T) *httptest.Server {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("expected User-Agent test-agent, got %q", ua)
+		}
+		if v := r.Header.Get("X-Test"); v != "yes" {
+			t.Errorf("expected X-Test header, got %q", v)
+		}
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<sitemapindex>
+<sitemap><loc>%[1]s/a.xml</loc></sitemap>
+<sitemap><loc>%[1]s/nested.xml</loc></sitemap>
+<sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+<sitemap><loc>%[1]s</loc></sitemap>
+</sitemapindex>`, ts.URL)
+		case "/nested.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>%s/b.xml</loc></sitemap></sitemapindex>`, ts.URL)
+		case "/a.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>http://example.com/a1</loc></url><url><loc>http://example.com/a2</loc></url></urlset>`)
+		case "/b.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>http://example.com/b1</loc></url></urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return ts
+}
+
+func TestFetchAll(t *testing.T) {
+	ts := indexServer(t)
+	defer ts.Close()
+
+	f := &Fetcher{
+		UserAgent:   "test-agent",
+		Header:      http.Header{"X-Test": []string{"yes"}},
+		Connections: 2,
+	}
+	entries, errs := f.FetchAll(ts.URL)
+
+	var locs []string
+	for _, e := range entries {
+		locs = append(locs, e.Loc)
+	}
+	if fmt.Sprint(locs) != "[http://example.com/a1 http://example.com/a2 http://example.com/b1]" {
+		t.Errorf("unexpected entries %v", locs)
+	}
+	if entries[2].Sitemap != ts.URL+"/b.xml" {
+		t.Errorf("expected entry from %s/b.xml, got %s", ts.URL, entries[2].Sitemap)
+	}
+
+	// The index lists itself and a sitemap that doesn't exist.
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %v", errs)
+	}
+	if e := errs[0].(*Error); e.Sitemap != ts.URL || e.Err != ErrRepeated {
+		t.Errorf("expected repeated sitemap, got %v", e)
+	}
+	if e := errs[1].(*Error); e.Sitemap != ts.URL+"/missing.xml" {
+		t.Errorf("expected missing sitemap, got %v", e)
+	}
+}
+
+func TestFetchAllLimits(t *testing.T) {
+	ts := indexServer(t)
+	defer ts.Close()
+
+	f := &Fetcher{
+		UserAgent: "test-agent",
+		Header:    http.Header{"X-Test": []string{"yes"}},
+		MaxDepth:  1,
+	}
+	entries, errs := f.FetchAll(ts.URL)
+	if len(entries) != 2 {
+		t.Errorf("expected 2 entries, got %d", len(entries))
+	}
+	var depthErr bool
+	for _, err := range errs {
+		if e := err.(*Error); e.Err == ErrMaxDepth && e.Sitemap == ts.URL+"/b.xml" {
+			depthErr = true
+		}
+	}
+	if !depthErr {
+		t.Errorf("expected %s/b.xml to be too deep, got %v", ts.URL, errs)
+	}
+
+	f.MaxDepth = 0
+	f.MaxURLs = 1
+	entries, errs = f.FetchAll(ts.URL)
+	if len(entries) != 1 {
+		t.Errorf("expected 1 entry, got %d", len(entries))
+	}
+	var limitErr bool
+	for _, err := range errs {
+		if err.(*Error).Err == ErrMaxURLs {
+			limitErr = true
+		}
+	}
+	if !limitErr {
+		t.Errorf("expected URL limit to be reported, got %v", errs)
+	}
+}
+
+func TestFetchAllTimeout(t *testing.T) {
+	done := make(chan bool)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer ts.Close()
+	defer close(done)
+
+	if defaultClient.Timeout == 0 {
+		t.Errorf("expected default client to have a timeout")
+	}
+
+	f := &Fetcher{Client: &http.Client{Timeout: 50 * time.Millisecond}}
+	entries, errs := f.FetchAll(ts.URL)
+	if len(entries) != 0 || len(errs) != 1 {
+		t.Errorf("expected a single error, got %v and %v", entries, errs)
+	}
+}
+
+func TestFetchAllTooLarge(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(make([]byte, LimitBytes+1))
+	}))
+	defer ts.Close()
+
+	f := &Fetcher{}
+	_, errs := f.FetchAll(ts.URL)
+	if len(errs) != 1 || errs[0].(*Error).Err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge, got %v", errs)
+	}
+}
@@ -0,0 +1,157 @@
+package sitemap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+)
+
+// A list of URLs to crawl may be in any format the protocol accepts
+// for a sitemap, or a CSV file such as one exported from another
+// tool.
+
+// utf8BOM begins many CSV files exported by spreadsheets.
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// DetectFormat guesses the format of the list in data: "xml", "rss"
+// or "atom" for XML according to its root element, "csv" if it has a
+// header row followed by a row containing a URL, and "text"
+// otherwise. A URL list isn't CSV just because a URL contains a
+// comma.
+func DetectFormat(data []byte) string {
+	data = bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM))
+	if bytes.HasPrefix(data, []byte("<")) {
+		switch root(data) {
+		case "rss", "RDF":
+			return "rss"
+		case "feed":
+			return "atom"
+		}
+		return "xml"
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err != nil || len(header) < 2 {
+		return "text"
+	}
+	for _, field := range header {
+		if isAbsURL(field) {
+			return "text"
+		}
+	}
+	row, err := r.Read()
+	if err != nil {
+		return "text"
+	}
+	for _, field := range row {
+		if isAbsURL(field) {
+			return "csv"
+		}
+	}
+	return "text"
+}
+
+// ParseCSV returns the URLs in a column of CSV data. column is either
+// the number of the column, counting from 1, or the name in its
+// header. When selecting by number, a first row that doesn't contain
+// a URL is taken to be a header and skipped.
+func ParseCSV(in io.Reader, column string) ([]string, error) {
+	data, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, utf8BOM)))
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	col, err := strconv.Atoi(column)
+	if err == nil {
+		if col < 1 {
+			return nil, fmt.Errorf("invalid column %d", col)
+		}
+		col--
+		if len(records[0]) <= col || !isAbsURL(records[0][col]) {
+			records = records[1:]
+		}
+	} else {
+		col = -1
+		for i, name := range records[0] {
+			if strings.EqualFold(strings.TrimSpace(name), column) {
+				col = i
+				break
+			}
+		}
+		if col < 0 {
+			return nil, fmt.Errorf("no column named %q", column)
+		}
+		records = records[1:]
+	}
+
+	var urls []string
+	for _, record := range records {
+		if col >= len(record) {
+			continue
+		}
+		if u := strings.TrimSpace(record[col]); u != "" {
+			urls = append(urls, u)
+		}
+	}
+	return urls, nil
+}
+
+// ParseList returns the URLs in a list, one per line, or the
+// addresses of the results in crawl data.
+func ParseList(in io.Reader) ([]string, error) {
+	body, err := ioutil.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var urls []string
+	if !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			if u := strings.TrimSpace(scanner.Text()); u != "" {
+				urls = append(urls, u)
+			}
+		}
+		return urls, scanner.Err()
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	for {
+		var r data.Result
+		err := dec.Decode(&r)
+		if err == io.EOF {
+			return urls, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.Address != nil {
+			urls = append(urls, r.Address.Full)
+		}
+	}
+}
+
+func isAbsURL(s string) bool {
+	u, err := url.Parse(strings.TrimSpace(s))
+	return err == nil && u.IsAbs()
+}
@@ -0,0 +1,93 @@
+package sitemap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDetectFormat(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want string
+	}{
+		{"http://example.com/a\nhttp://example.com/b\n", "text"},
+		{"http://example.com/a,b\nhttp://example.com/c\n", "text"},
+		{"\xef\xbb\xbfAddress,Status\nhttp://example.com/a,200\n", "csv"},
+		{"Address,Status\nnot a URL,200\n", "text"},
+		{"Address\nhttp://example.com/a\n", "text"},
+		{`<?xml version="1.0"?><urlset><url><loc>http://example.com/</loc></url></urlset>`, "xml"},
+		{`<rss version="2.0"><channel></channel></rss>`, "rss"},
+		{`<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"></rdf:RDF>`, "rss"},
+		{`<feed xmlns="http://www.w3.org/2005/Atom"></feed>`, "atom"},
+		{"", "text"},
+	} {
+		if got := DetectFormat([]byte(tt.data)); got != tt.want {
+			t.Errorf("expected %s, got %s for %q", tt.want, got, tt.data)
+		}
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	const withHeader = "\xef\xbb\xbfStatus,Address\n200,http://example.com/a\n301, http://example.com/b \n404\n"
+	const withoutHeader = "http://example.com/a,200\nhttp://example.com/b,301\n"
+
+	for _, tt := range []struct {
+		data   string
+		column string
+		want   string
+		err    bool
+	}{
+		{withHeader, "2", "[http://example.com/a http://example.com/b]", false},
+		{withHeader, "address", "[http://example.com/a http://example.com/b]", false},
+		{withHeader, "URL", "", true},
+		{withHeader, "0", "", true},
+		{withoutHeader, "1", "[http://example.com/a http://example.com/b]", false},
+		{"", "1", "[]", false},
+	} {
+		urls, err := ParseCSV(strings.NewReader(tt.data), tt.column)
+		if tt.err {
+			if err == nil {
+				t.Errorf("column %s: expected error for %q", tt.column, tt.data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("column %s: unexpected error %v for %q", tt.column, err, tt.data)
+			continue
+		}
+		if got := fmt.Sprint(urls); got != tt.want {
+			t.Errorf("column %s: expected %s, got %s for %q", tt.column, tt.want, got, tt.data)
+		}
+	}
+}
+
+func TestParseList(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want string
+		err  bool
+	}{
+		{"http://example.com/a\n\n  http://example.com/b  \n", "[http://example.com/a http://example.com/b]", false},
+		{`{"Address":{"Full":"http://example.com/a"}}
+{"Depth":1}
+{"Address":{"Full":"http://example.com/b"}}`, "[http://example.com/a http://example.com/b]", false},
+		{`{"Address":`, "", true},
+		{"", "[]", false},
+	} {
+		urls, err := ParseList(strings.NewReader(tt.data))
+		if tt.err {
+			if err == nil {
+				t.Errorf("expected error for %q", tt.data)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error %v for %q", err, tt.data)
+			continue
+		}
+		if got := fmt.Sprint(urls); got != tt.want {
+			t.Errorf("expected %s, got %s for %q", tt.want, got, tt.data)
+		}
+	}
+}
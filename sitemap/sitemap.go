@@ -3,7 +3,10 @@
 package sitemap
 
 import (
+	"bytes"
+	"compress/gzip"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -18,7 +21,22 @@ import (
 // Sitemap
 
 type urlset struct {
-	URLs []string `xml:"url>loc"`
+	URLs []*Entry `xml:"url"`
+}
+
+// Entry is a URL listed in a sitemap, with its optional metadata and
+// extensions as written in the sitemap. Sitemap is the location of
+// the sitemap the entry was read from, if known.
+type Entry struct {
+	Loc        string       `xml:"loc"`
+	Lastmod    string       `xml:"lastmod"`
+	Changefreq string       `xml:"changefreq"`
+	Priority   string       `xml:"priority"`
+	Images     []*Image     `xml:"image"`
+	Videos     []*Video     `xml:"video"`
+	News       *News        `xml:"news"`
+	Alternates []*Alternate `xml:"link"`
+	Sitemap    string       `xml:"-"`
 }
 
 // Sitemap index
@@ -30,11 +48,32 @@ type index struct {
 // Parse interprets in as a sitemap. It returns the URLs in that
 // sitemap if successful.
 func Parse(in io.Reader) ([]string, error) {
-	data, err := ioutil.ReadAll(in)
+	entries, err := ParseEntries(in)
+	if err != nil {
+		return nil, err
+	}
+	return locs(entries), nil
+}
+
+// ParseEntries is like Parse, but it returns each URL with its
+// metadata.
+func ParseEntries(in io.Reader) ([]*Entry, error) {
+	data, err := readLimited(in)
+	if err == ErrTooLarge {
+		return nil, err
+	}
 	if err != nil {
 		return nil, fmt.Errorf("Parse couldn't read sitemap data: %v", err)
 	}
 
+	data, err = decompress(data)
+	if err == ErrTooLarge {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Parse couldn't decompress sitemap data: %v", err)
+	}
+
 	res := &urlset{}
 
 	err = xml.Unmarshal(data, res)
@@ -42,13 +81,67 @@ func Parse(in io.Reader) ([]string, error) {
 		return nil, fmt.Errorf("Parse failed to unmarshal sitemap data: %v", err)
 	}
 
+	for _, e := range res.URLs {
+		e.trim()
+	}
+
 	return res.URLs, nil
 }
 
+func locs(entries []*Entry) []string {
+	var urls []string
+	for _, e := range entries {
+		urls = append(urls, e.Loc)
+	}
+	return urls
+}
+
+// gzipMagic begins every gzip stream.
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ErrTooLarge is reported for a sitemap or index that is, or expands
+// to, more than LimitBytes.
+var ErrTooLarge = errors.New("uncompressed size exceeds limit")
+
+// readLimited reads in, but no more than LimitBytes, so that a huge
+// response can't exhaust memory.
+func readLimited(in io.Reader) ([]byte, error) {
+	data, err := ioutil.ReadAll(io.LimitReader(in, LimitBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > LimitBytes {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// decompress returns the content of data if it is gzip-compressed,
+// and data itself otherwise. Compressed sitemaps are recognized by
+// their content, since they aren't always served with a .gz
+// extension or a matching Content-Type. No more than LimitBytes are
+// decompressed, so that a small file can't exhaust memory.
+func decompress(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, gzipMagic) {
+		return data, nil
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return readLimited(zr)
+}
+
 // ParseIndex interprets in as a sitemap index. It returns the sitemap
 // URLs in the index if successful.
 func ParseIndex(in io.Reader) ([]string, error) {
-	data, err := ioutil.ReadAll(in)
+	data, err := readLimited(in)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = decompress(data)
 	if err != nil {
 		return nil, err
 	}
@@ -66,18 +159,32 @@ func ParseIndex(in io.Reader) ([]string, error) {
 // Fetch is like Parse, but it also retrieves its data from the given
 // URL.
 func Fetch(url string) ([]string, error) {
+	entries, err := FetchEntries(url)
+	if err != nil {
+		return nil, err
+	}
+	return locs(entries), nil
+}
+
+// FetchEntries is like ParseEntries, but it also retrieves its data
+// from the given URL. Each entry records url as its Sitemap.
+func FetchEntries(url string) ([]*Entry, error) {
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
 
-	urls, err := Parse(resp.Body)
+	entries, err := ParseEntries(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
-	return urls, nil
+	for _, e := range entries {
+		e.Sitemap = url
+	}
+
+	return entries, nil
 }
 
 // FetchIndex is like ParseIndex, but it also retrieves its data from
@@ -1,12 +1,19 @@
 package sitemap
 
 import (
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/benjaminestes/crawl/crawler/data"
 )
 
 func TestFetch(t *testing.T) {
@@ -70,3 +77,322 @@ func TestInvalidData(t *testing.T) {
 		t.Errorf("FetchIndex should've reported an error")
 	}
 }
+
+func TestFetchEntries(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/sitemap-meta.xml")
+	}))
+	defer ts.Close()
+
+	entries, err := FetchEntries(ts.URL)
+	if err != nil {
+		t.Fatalf("couldn't retrieve test sitemap: %v", err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+	e := entries[0]
+	if e.Lastmod != "2005-01-01" || e.Changefreq != "monthly" || e.Priority != "0.8" || e.Sitemap != ts.URL {
+		t.Errorf("unexpected entry %+v", e)
+	}
+	if loc := entries[1].Loc; loc != "http://www.example.com/catalog?item=12&desc=vacation_hawaii" {
+		t.Errorf("unexpected loc %q", loc)
+	}
+}
+
+func TestDecompressLimit(t *testing.T) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	zw.Write(make([]byte, LimitBytes+1))
+	zw.Close()
+
+	if _, err := decompress(buf.Bytes()); err != ErrTooLarge {
+		t.Errorf("expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	data := make([]byte, LimitBytes+1)
+	if _, err := ParseEntries(bytes.NewReader(data)); err != ErrTooLarge {
+		t.Errorf("ParseEntries: expected ErrTooLarge, got %v", err)
+	}
+	if _, err := ParseIndex(bytes.NewReader(data)); err != ErrTooLarge {
+		t.Errorf("ParseIndex: expected ErrTooLarge, got %v", err)
+	}
+}
+
+func TestFetchGzip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Serve without a .gz extension, so the content must be
+		// recognized by its magic bytes.
+		data, err := ioutil.ReadFile("testdata" + r.URL.Path + ".gz")
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(data)
+	}))
+	defer ts.Close()
+
+	urls, err := Fetch(ts.URL + "/sitemap.xml")
+	if err != nil {
+		t.Fatalf("couldn't retrieve compressed sitemap: %v", err)
+	}
+	if len(urls) != 1 {
+		t.Errorf("expected 1 URL, got %d", len(urls))
+	}
+
+	sitemaps, err := FetchIndex(ts.URL + "/sitemap-index.xml")
+	if err != nil {
+		t.Fatalf("couldn't retrieve compressed sitemap index: %v", err)
+	}
+	if len(sitemaps) != 2 {
+		t.Errorf("expected 2 URLs, got %d", len(sitemaps))
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	f, err := os.Open("testdata/sitemap-ext.xml")
+	if err != nil {
+		t.Fatalf("couldn't open sitemap-ext.xml for reading")
+	}
+	defer f.Close()
+
+	entries, err := ParseEntries(f)
+	if err != nil {
+		t.Fatalf("couldn't parse test sitemap: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(entries))
+	}
+
+	page := entries[0]
+	if len(page.Alternates) != 2 {
+		t.Fatalf("expected 2 alternates, got %d", len(page.Alternates))
+	}
+	if a := page.Alternates[0]; a.Rel != "alternate" || a.Hreflang != "de" || a.Href != "http://www.example.com/deutsch/page.html" {
+		t.Errorf("unexpected alternate %+v", a)
+	}
+	if len(page.Images) != 2 || page.Images[0].Caption != "A caption" || page.Images[1].Loc != "http://example.com/photo.jpg" {
+		t.Errorf("unexpected images %+v", page.Images)
+	}
+	if page.News != nil || len(page.Videos) != 0 {
+		t.Errorf("unexpected news or video on %s", page.Loc)
+	}
+
+	video := entries[1]
+	if len(video.Videos) != 1 {
+		t.Fatalf("expected 1 video, got %d", len(video.Videos))
+	}
+	if v := video.Videos[0]; v.Title != "Grilling steaks for summer" || v.Duration != "600" || len(v.Tags) != 2 {
+		t.Errorf("unexpected video %+v", v)
+	}
+	if n := video.News; n == nil || n.PublicationName != "The Example Times" || n.PublicationLanguage != "en" || n.PublicationDate != "2008-12-23" {
+		t.Errorf("unexpected news %+v", n)
+	}
+}
+
+func TestDiscover(t *testing.T) {
+	var indexRequests int
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/robots.txt":
+			fmt.Fprintf(w, "User-agent: *\nDisallow:\n\nSitemap: %s/listed.xml # news\n", ts.URL)
+		case "/sitemap_index.xml":
+			indexRequests++
+			http.ServeFile(w, r, "testdata/sitemap-index.xml")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	sitemaps, err := (&Fetcher{}).Discover(ts.URL + "/some/page")
+	if err != nil {
+		t.Fatalf("couldn't discover sitemaps: %v", err)
+	}
+	want := []string{ts.URL + "/listed.xml", ts.URL + "/sitemap_index.xml"}
+	if fmt.Sprint(sitemaps) != fmt.Sprint(want) {
+		t.Errorf("expected %v, got %v", want, sitemaps)
+	}
+
+	// The index was read to discover it, so isn't requested again.
+	// The sitemaps it lists can't be requested.
+	offline := &Fetcher{Client: &http.Client{Transport: offlineTransport{}}}
+	_, errs := offline.FetchDiscovered(sitemaps[1])
+	if indexRequests != 1 || len(errs) != 2 {
+		t.Errorf("expected index to be requested once, got %d, with errors %v", indexRequests, errs)
+	}
+}
+
+// offlineTransport fails every request.
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("offline")
+}
+
+func TestValidator(t *testing.T) {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			fmt.Fprintf(w, `<sitemapindex>
+<sitemap><loc>%[1]s/a.xml</loc></sitemap>
+<sitemap><loc>%[1]s/b.xml</loc></sitemap>
+<sitemap><loc>%[1]s/empty.xml</loc></sitemap>
+<sitemap><loc>%[1]s/missing.xml</loc></sitemap>
+</sitemapindex>`, ts.URL)
+		case "/a.xml":
+			fmt.Fprintf(w, `<urlset>
+<url><loc>%[1]s/ok</loc><lastmod>2004-12-23T18:00:15+00:00</lastmod><changefreq>daily</changefreq><priority>0.5</priority></url>
+<url><loc>%[1]s/bad</loc><lastmod>23/12/2004</lastmod><changefreq>sometimes</changefreq><priority>2</priority></url>
+<url><loc>http://other.example.com/</loc></url>
+</urlset>`, ts.URL)
+		case "/b.xml":
+			fmt.Fprintf(w, `<urlset><url><loc>%s/ok</loc><lastmod>2004-12</lastmod></url></urlset>`, ts.URL)
+		case "/empty.xml":
+			fmt.Fprintf(w, `<urlset></urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	v := &Validator{}
+	f := &Fetcher{Validator: v}
+	_, errs := f.FetchAll(ts.URL)
+	for _, err := range errs {
+		v.AddError(err)
+	}
+
+	var got []string
+	for _, finding := range v.Findings {
+		got = append(got, finding.Issue+" "+strings.TrimPrefix(finding.Loc, ts.URL))
+	}
+	want := []string{
+		"invalid lastmod /bad",
+		"invalid changefreq /bad",
+		"invalid priority /bad",
+		"URL not on sitemap host http://other.example.com/",
+		"duplicate URL /ok",
+		"no URLs ",
+		"unavailable ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected findings:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestValidatorAddResult(t *testing.T) {
+	page := func(addr string, reasons ...string) *data.Result {
+		r := &data.Result{}
+		r.Address = data.MakeAddress(addr)
+		r.SitemapEntry = &data.SitemapEntry{Sitemap: "http://example.com/sitemap.xml"}
+		r.NonIndexableReasons = reasons
+		return r
+	}
+
+	redirect := page("http://example.com/old", data.ReasonRedirect)
+	redirect.ResolvesTo = data.MakeAddress("http://example.com/new")
+	missing := page("http://example.com/missing", data.ReasonStatus)
+	missing.Status = "404 Not Found"
+	canonicalized := page("http://example.com/copy", data.ReasonCanonicalized)
+	canonicalized.Canonical = data.MakeCanonical(canonicalized.Address, "http://example.com/original")
+	noindex := page("http://example.com/private", data.ReasonNoindex)
+	unlisted := page("http://example.com/unlisted", data.ReasonNoindex)
+	unlisted.SitemapEntry = nil
+
+	v := &Validator{}
+	for _, r := range []*data.Result{page("http://example.com/ok"), redirect, missing, canonicalized, noindex, unlisted} {
+		v.AddResult(r)
+	}
+
+	var got []string
+	for _, f := range v.Findings {
+		got = append(got, fmt.Sprintf("%s %s: %s", f.Loc, f.Issue, f.Detail))
+	}
+	want := []string{
+		"http://example.com/old redirect: http://example.com/new",
+		"http://example.com/missing non-200 status: 404 Not Found",
+		"http://example.com/copy canonicalized: http://example.com/original",
+		"http://example.com/private noindex: ",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("expected findings:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestParseFeeds(t *testing.T) {
+	tests := []struct {
+		file    string
+		parse   func(io.Reader) ([]*Entry, error)
+		lastmod string
+	}{
+		{"testdata/feed.rss", ParseRSS, "2003-06-10T04:00:00Z"},
+		{"testdata/feed.atom", ParseAtom, "2003-12-13T18:30:02Z"},
+	}
+
+	for _, test := range tests {
+		f, err := os.Open(test.file)
+		if err != nil {
+			t.Fatalf("couldn't open %s for reading", test.file)
+		}
+		entries, err := test.parse(f)
+		f.Close()
+		if err != nil {
+			t.Errorf("couldn't parse %s: %v", test.file, err)
+			continue
+		}
+		if len(entries) != 2 {
+			t.Errorf("%s: expected 2 entries, got %d", test.file, len(entries))
+			continue
+		}
+		if entries[0].Loc != "http://www.example.com/first" || entries[1].Loc != "http://www.example.com/second" {
+			t.Errorf("%s: unexpected URLs %s, %s", test.file, entries[0].Loc, entries[1].Loc)
+		}
+		if entries[0].Lastmod != test.lastmod {
+			t.Errorf("%s: expected lastmod %q, got %q", test.file, test.lastmod, entries[0].Lastmod)
+		}
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var entries []*Entry
+	for i := 0; i <= LimitURLs; i++ {
+		entries = append(entries, &Entry{Loc: fmt.Sprintf("http://www.example.com/%d?a=1&b=2", i)})
+	}
+	entries[0].Lastmod = "2005-01-01T00:00:00Z"
+
+	groups, err := Split(entries)
+	if err != nil {
+		t.Fatalf("couldn't split entries: %v", err)
+	}
+	if len(groups) != 2 || len(groups[0]) != LimitURLs || len(groups[1]) != 1 {
+		t.Fatalf("expected groups of %d and 1 entries, got %d groups", LimitURLs, len(groups))
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, groups[0]); err != nil {
+		t.Fatalf("couldn't write sitemap: %v", err)
+	}
+	parsed, err := ParseEntries(&buf)
+	if err != nil {
+		t.Fatalf("couldn't parse written sitemap: %v", err)
+	}
+	if len(parsed) != LimitURLs || parsed[0].Loc != entries[0].Loc || parsed[0].Lastmod != entries[0].Lastmod {
+		t.Errorf("written sitemap doesn't match entries")
+	}
+
+	buf.Reset()
+	if err := WriteIndex(&buf, []string{"http://www.example.com/sitemap-1.xml"}); err != nil {
+		t.Fatalf("couldn't write index: %v", err)
+	}
+	sitemaps, err := ParseIndex(&buf)
+	if err != nil || len(sitemaps) != 1 || sitemaps[0] != "http://www.example.com/sitemap-1.xml" {
+		t.Errorf("written index doesn't match sitemaps: %v %v", sitemaps, err)
+	}
+}
@@ -0,0 +1,200 @@
+package sitemap
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/benjaminestes/crawl/crawler/data"
+)
+
+// These are the limits the protocol places on a single sitemap or
+// index. LimitBytes applies to the uncompressed file.
+const (
+	LimitURLs  = 50000
+	LimitBytes = 50 * 1024 * 1024
+)
+
+// These are the issues a Validator may find, as recorded in
+// Finding.Issue.
+const (
+	IssueUnavailable = "unavailable"
+	IssueTooManyURLs = "too many URLs"
+	IssueTooLarge    = "too large"
+	IssueInvalidURL  = "invalid URL"
+	IssueHost        = "URL not on sitemap host"
+	IssueLastmod     = "invalid lastmod"
+	IssueChangefreq  = "invalid changefreq"
+	IssuePriority    = "invalid priority"
+	IssueDuplicate   = "duplicate URL"
+	IssueEmpty       = "no URLs"
+)
+
+// Finding is a way in which a sitemap departs from the protocol, or
+// in which a URL it lists is unsuitable for a sitemap. Loc is the URL
+// the finding concerns, if it concerns a single URL. Issue is one of
+// the Issue constants, or for a problem found by crawling Loc, the
+// reason it isn't indexable.
+type Finding struct {
+	Sitemap string `mode:"REQUIRED"`
+	Loc     string
+	Issue   string `mode:"REQUIRED"`
+	Detail  string
+}
+
+// Validator checks sitemaps for problems with respect to the
+// protocol. Set it as the Validator of a Fetcher to check every
+// sitemap the Fetcher reads.
+type Validator struct {
+	Findings []*Finding
+
+	// seen maps each URL to the first sitemap listing it.
+	seen map[string]string
+}
+
+func (v *Validator) add(sitemap, loc, issue, detail string) {
+	v.Findings = append(v.Findings, &Finding{sitemap, loc, issue, detail})
+}
+
+// AddError records a problem reported by Fetcher.FetchAll.
+func (v *Validator) AddError(err error) {
+	if e, ok := err.(*Error); ok {
+		if e.Err == ErrTooLarge {
+			v.add(e.Sitemap, "", IssueTooLarge, fmt.Sprintf("more than %d bytes uncompressed", LimitBytes))
+			return
+		}
+		v.add(e.Sitemap, "", IssueUnavailable, e.Err.Error())
+		return
+	}
+	v.add("", "", IssueUnavailable, err.Error())
+}
+
+// AddResult records the reasons the page described by r, the result
+// of crawling a URL listed in a sitemap, should not be in a sitemap.
+// Each reason the page isn't indexable is a finding.
+func (v *Validator) AddResult(r *data.Result) {
+	if r.Address == nil || r.SitemapEntry == nil {
+		return
+	}
+	for _, reason := range r.NonIndexableReasons {
+		var detail string
+		switch reason {
+		case data.ReasonRedirect:
+			if r.ResolvesTo != nil {
+				detail = r.ResolvesTo.Full
+			}
+		case data.ReasonStatus:
+			detail = r.Status
+		case data.ReasonCanonicalized:
+			detail = canonicalElsewhere(r)
+		}
+		v.add(r.SitemapEntry.Sitemap, r.Address.Full, reason, detail)
+	}
+}
+
+// canonicalElsewhere returns the first canonical URL declared by the
+// page described by r that isn't its own.
+func canonicalElsewhere(r *data.Result) string {
+	canonicals := r.Canonicals
+	if r.Canonical != nil {
+		canonicals = append([]*data.Canonical{r.Canonical}, canonicals...)
+	}
+	for _, c := range canonicals {
+		if c.Address != nil && c.Address.Full != r.Address.Full {
+			return c.Address.Full
+		}
+	}
+	return ""
+}
+
+// checkIndex validates the index at loc, of size bytes.
+func (v *Validator) checkIndex(loc string, size int, sitemaps []string) {
+	if len(sitemaps) == 0 {
+		v.add(loc, "", IssueEmpty, "")
+	}
+	if size > LimitBytes {
+		v.add(loc, "", IssueTooLarge, fmt.Sprintf("%d bytes", size))
+	}
+	if len(sitemaps) > LimitURLs {
+		v.add(loc, "", IssueTooManyURLs, fmt.Sprintf("%d sitemaps", len(sitemaps)))
+	}
+}
+
+// checkSitemap validates the sitemap at loc, of size bytes.
+func (v *Validator) checkSitemap(loc string, size int, entries []*Entry) {
+	if v.seen == nil {
+		v.seen = make(map[string]string)
+	}
+	if len(entries) == 0 {
+		v.add(loc, "", IssueEmpty, "")
+	}
+	if size > LimitBytes {
+		v.add(loc, "", IssueTooLarge, fmt.Sprintf("%d bytes", size))
+	}
+	if len(entries) > LimitURLs {
+		v.add(loc, "", IssueTooManyURLs, fmt.Sprintf("%d URLs", len(entries)))
+	}
+
+	base, _ := url.Parse(loc)
+	for _, e := range entries {
+		if first, ok := v.seen[e.Loc]; ok {
+			v.add(loc, e.Loc, IssueDuplicate, "first listed in "+first)
+		} else {
+			v.seen[e.Loc] = loc
+		}
+
+		u, err := url.Parse(e.Loc)
+		switch {
+		case err != nil || !u.IsAbs():
+			v.add(loc, e.Loc, IssueInvalidURL, "")
+		case base != nil && (u.Scheme != base.Scheme || !strings.EqualFold(u.Host, base.Host)):
+			v.add(loc, e.Loc, IssueHost, "sitemap is on "+base.Scheme+"://"+base.Host)
+		}
+
+		if e.Lastmod != "" && !isW3CDatetime(e.Lastmod) {
+			v.add(loc, e.Loc, IssueLastmod, e.Lastmod)
+		}
+		if e.Changefreq != "" && !changefreqs[e.Changefreq] {
+			v.add(loc, e.Loc, IssueChangefreq, e.Changefreq)
+		}
+		if e.Priority != "" {
+			p, err := strconv.ParseFloat(e.Priority, 64)
+			if err != nil || p < 0 || p > 1 {
+				v.add(loc, e.Loc, IssuePriority, e.Priority)
+			}
+		}
+	}
+}
+
+var changefreqs = map[string]bool{
+	"always":  true,
+	"hourly":  true,
+	"daily":   true,
+	"weekly":  true,
+	"monthly": true,
+	"yearly":  true,
+	"never":   true,
+}
+
+// w3cDatetime lists the layouts of the W3C Datetime format. A
+// fractional second is accepted by the last.
+//
+// Specification: https://www.w3.org/TR/NOTE-datetime
+var w3cDatetime = []string{
+	"2006",
+	"2006-01",
+	"2006-01-02",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05Z07:00",
+}
+
+func isW3CDatetime(s string) bool {
+	for _, layout := range w3cDatetime {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+package sitemap
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// xmlns is the namespace of sitemaps and indexes.
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+const (
+	urlsetOpen  = `<urlset xmlns="` + xmlns + `">` + "\n"
+	urlsetClose = "</urlset>\n"
+	indexOpen   = `<sitemapindex xmlns="` + xmlns + `">` + "\n"
+	indexClose  = "</sitemapindex>\n"
+)
+
+// urlOut is the form in which an Entry is written. Extensions are
+// not written.
+type urlOut struct {
+	XMLName    xml.Name `xml:"url"`
+	Loc        string   `xml:"loc"`
+	Lastmod    string   `xml:"lastmod,omitempty"`
+	Changefreq string   `xml:"changefreq,omitempty"`
+	Priority   string   `xml:"priority,omitempty"`
+}
+
+type sitemapOut struct {
+	XMLName xml.Name `xml:"sitemap"`
+	Loc     string   `xml:"loc"`
+}
+
+func encodeEntry(e *Entry) ([]byte, error) {
+	b, err := xml.Marshal(&urlOut{
+		Loc:        e.Loc,
+		Lastmod:    e.Lastmod,
+		Changefreq: e.Changefreq,
+		Priority:   e.Priority,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// Split divides entries into groups that can each be written as a
+// sitemap within LimitURLs and LimitBytes.
+func Split(entries []*Entry) ([][]*Entry, error) {
+	var groups [][]*Entry
+	var group []*Entry
+	empty := len(xml.Header) + len(urlsetOpen) + len(urlsetClose)
+	size := empty
+	for _, e := range entries {
+		b, err := encodeEntry(e)
+		if err != nil {
+			return nil, err
+		}
+		if len(group) > 0 && (len(group) == LimitURLs || size+len(b) > LimitBytes) {
+			groups = append(groups, group)
+			group, size = nil, empty
+		}
+		group = append(group, e)
+		size += len(b)
+	}
+	if len(group) > 0 {
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// Write writes entries to w as a sitemap. It doesn't enforce the
+// protocol's limits; see Split.
+func Write(w io.Writer, entries []*Entry) error {
+	if _, err := io.WriteString(w, xml.Header+urlsetOpen); err != nil {
+		return err
+	}
+	for _, e := range entries {
+		b, err := encodeEntry(e)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, urlsetClose)
+	return err
+}
+
+// WriteIndex writes a sitemap index listing sitemaps to w.
+func WriteIndex(w io.Writer, sitemaps []string) error {
+	if _, err := io.WriteString(w, xml.Header+indexOpen); err != nil {
+		return err
+	}
+	for _, loc := range sitemaps {
+		b, err := xml.Marshal(&sitemapOut{Loc: loc})
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(w, indexClose)
+	return err
+}
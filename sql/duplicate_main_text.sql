@@ -0,0 +1,14 @@
+-- Like duplicate_body.sql, but groups pages by the hash of their main
+-- content only, so that pages sharing a template but not their
+-- content are not reported.
+WITH q AS (SELECT * FROM crawl)
+
+SELECT ARRAY_AGG(DISTINCT Address.Full) AS Examples,
+       MainTextHash,
+       ANY_VALUE(MainWordCount) AS MainWordCount,
+       COUNT(*) AS N
+FROM q
+WHERE MainTextHash IS NOT NULL
+  AND MainWordCount > 0
+GROUP BY MainTextHash
+ORDER BY N DESC
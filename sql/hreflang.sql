@@ -1,42 +1,69 @@
--- Produces a table with one row per hreflang tag seen in the crawl.
--- This row includes the source address, the target address, whether
--- the hreflang relationship is reciprocated, the language of the
--- hreflang tag, and the status code of the target page.
+-- Produces a table with one row per hreflang tag seen in the crawl,
+-- whether declared on the page or in a sitemap. This row includes the
+-- source address, the target address, whether the hreflang
+-- relationship is reciprocated, the language of the hreflang tag, and
+-- the status code of the target page.
 WITH
         q AS ( -- project
         SELECT
                 Address.Full AS FullAddress,
                 Hreflang,
-                StatusCode
+                IFNULL(SitemapEntry.Hreflang, []) AS SitemapHreflang,
+                StatusCode,
+                Language
         FROM crawl ), -- your crawl here!
-	
+
 	-- `r` represents all the URLs which are targets of some
 	-- hreflang tag that was seen in a crawl. These URLs may or
 	-- may not have been seen themselves.  FullAddress = address
 	-- of a target page, SourceAddress = URL that targeted it,
-	-- HreflangCode = language specified by tag that targeted it.
+	-- HreflangCode = language specified by tag that targeted it,
+	-- Source = where the tag was declared (html, header or
+	-- sitemap). OnPage and InSitemap say whether the source
+	-- declared the same alternate on the page and in a sitemap.
 	r AS ( -- reciprocal analysis
         SELECT DISTINCT
                 source.FullAddress AS SourceAddress,
                 target.Address.Full AS FullAddress,
-                target.Hreflang AS HreflangCode
-        FROM q AS source, UNNEST(Hreflang) AS target )
+                target.Hreflang AS HreflangCode,
+                target.Source AS Source,
+                EXISTS (SELECT 1 FROM UNNEST(source.Hreflang) AS h
+                        WHERE h.Address.Full = target.Address.Full
+                        AND h.Hreflang = target.Hreflang) AS OnPage,
+                EXISTS (SELECT 1 FROM UNNEST(source.SitemapHreflang) AS h
+                        WHERE h.Address.Full = target.Address.Full
+                        AND h.Hreflang = target.Hreflang) AS InSitemap
+        FROM q AS source,
+                UNNEST(ARRAY_CONCAT(source.Hreflang, source.SitemapHreflang)) AS target )
 
 -- Create one row for each URL that is the target of an hreflang tag;
 -- i.e., in this table information in `q` is information about the
 -- target page, not the source page.  For the targeted URL, show:
 
 -- the source address (which must exist), the target, the language of
--- the hreflang tag, whether the source address appears in the
--- (possibly empty) set of hreflang tags on the target page, and the
--- status code of the target address.
+-- the hreflang tag, where it was declared, whether the source address
+-- appears in the (possibly empty) set of hreflang tags on the target
+-- page or in its sitemap entry, and the status code of the target
+-- address. A sitemap-declared alternate that isn't also OnPage (or
+-- vice versa) means the two declarations disagree.
 SELECT DISTINCT
         SourceAddress,
         FullAddress AS TargetAddress,
         HreflangCode,
+        Source,
+        OnPage,
+        InSitemap,
         SourceAddress IN
-                (SELECT Address.Full FROM UNNEST(q.Hreflang))
+                (SELECT Address.Full FROM UNNEST(ARRAY_CONCAT(q.Hreflang, q.SitemapHreflang)))
                 AS Reciprocated,
-        q.StatusCode AS TargetStatusCode
+        q.StatusCode AS TargetStatusCode,
+        q.Language.Detected AS TargetDetectedLanguage,
+        HreflangCode != "x-default"
+                AND q.Language.Detected IS NOT NULL
+                AND q.Language.Detected != ""
+                -- Only languages the crawler can detect are compared.
+                AND LOWER(SPLIT(HreflangCode, "-")[OFFSET(0)])
+                        IN ("da", "de", "en", "es", "fr", "it", "nl", "pl", "pt", "sv")
+                AND q.Language.Detected != LOWER(SPLIT(HreflangCode, "-")[OFFSET(0)])
+                AS LanguageMismatch
 FROM r LEFT JOIN q USING (FullAddress)
-
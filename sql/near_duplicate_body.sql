@@ -0,0 +1,14 @@
+-- Pair up pages whose SimHash fingerprints differ in at most 3 of
+-- their 64 bits. Unlike duplicate_body.sql, this also finds pages
+-- whose body text differs slightly, e.g. by a date. For large crawls,
+-- `crawl dupes` groups pages more efficiently.
+WITH q AS (SELECT * FROM crawl WHERE SimHash IS NOT NULL)
+
+SELECT a.Address.Full AS Address,
+       b.Address.Full AS NearDuplicate,
+       BIT_COUNT(a.SimHash ^ b.SimHash) AS Distance
+FROM q AS a
+JOIN q AS b
+ON a.Address.Full < b.Address.Full
+   AND BIT_COUNT(a.SimHash ^ b.SimHash) <= 3
+ORDER BY Distance ASC
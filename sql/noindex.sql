@@ -1,4 +1,5 @@
--- List all addresses and whether they have a noindex tag
+-- List all addresses and whether they have a noindex directive, in
+-- a robots meta tag or an X-Robots-Tag header.
 SELECT Address,
-       REGEXP_CONTAINS(Robots, r"\bnoindex\b") AS Noindex
+       COALESCE(Indexability.Noindex, false) AS Noindex
 FROM crawl
@@ -6,9 +6,8 @@ WITH
         q AS ( -- extend                                                                                                                                                                      
         SELECT
                 *,
-                COALESCE(Address.Full != Canonical.Address.Full, true) AS HasOtherCanonical,
-                REGEXP_CONTAINS(Robots, r"\bnoindex\b") AS Noindex,
-                REGEXP_CONTAINS(Robots, r"\bnofollow\b") AS Nofollow
+                COALESCE(Indexability.Noindex, false) AS Noindex,
+                COALESCE(Indexability.Nofollow, false) AS Nofollow
         FROM crawl ), -- your crawl here                                                                                                                                                       
 
         r AS ( -- count links to each page                                                                                                                                                    
@@ -33,7 +32,8 @@ SELECT DISTINCT
         Robots,
         Noindex,
         Nofollow,
-        NOT (StatusCode != 200 OR Noindex OR HasOtherCanonical) AS Indexable,
+        Indexable,
+        ARRAY_TO_STRING(NonIndexableReasons, ", ") AS NonIndexableReasons,
         InLinks,
         BodyTextHash,
         COUNT(*) OVER (PARTITION BY BodyTextHash) AS BodyCount